@@ -176,6 +176,52 @@ func (SessionStatus) EnumDescriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{2}
 }
 
+type StateKind int32
+
+const (
+	StateKind_DELTA    StateKind = 0 // changes since the previous state
+	StateKind_SNAPSHOT StateKind = 1 // the whole session, the client must drop what it has.
+)
+
+// Enum value maps for StateKind.
+var (
+	StateKind_name = map[int32]string{
+		0: "DELTA",
+		1: "SNAPSHOT",
+	}
+	StateKind_value = map[string]int32{
+		"DELTA":    0,
+		"SNAPSHOT": 1,
+	}
+)
+
+func (x StateKind) Enum() *StateKind {
+	p := new(StateKind)
+	*p = x
+	return p
+}
+
+func (x StateKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StateKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_server_api_proto_enumTypes[3].Descriptor()
+}
+
+func (StateKind) Type() protoreflect.EnumType {
+	return &file_api_v1_server_api_proto_enumTypes[3]
+}
+
+func (x StateKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StateKind.Descriptor instead.
+func (StateKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{3}
+}
+
 type Coordintates struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -307,9 +353,10 @@ type Connector struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId      int32         `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	Transport   Transport     `protobuf:"varint,2,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"`
-	Destination *Coordintates `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Transport     Transport              `protobuf:"varint,2,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"`
+	Destination   *Coordintates          `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	InServiceFrom *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=inServiceFrom,proto3" json:"inServiceFrom,omitempty"` // set while the route is being upgraded
 }
 
 func (x *Connector) Reset() {
@@ -365,6 +412,13 @@ func (x *Connector) GetDestination() *Coordintates {
 	return nil
 }
 
+func (x *Connector) GetInServiceFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InServiceFrom
+	}
+	return nil
+}
+
 type Block struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -447,6 +501,11 @@ type Session struct {
 	TimeLimit *durationpb.Duration   `protobuf:"bytes,4,opt,name=timeLimit,proto3" json:"timeLimit,omitempty"`
 	Status    SessionStatus          `protobuf:"varint,5,opt,name=status,proto3,enum=SessionStatus" json:"status,omitempty"`
 	StartTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	Mode      string                 `protobuf:"bytes,7,opt,name=mode,proto3" json:"mode,omitempty"`    // rules preset the session is played with
+	Width     int32                  `protobuf:"varint,8,opt,name=width,proto3" json:"width,omitempty"` // map size in blocks, the map is stored row by row
+	Height    int32                  `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
+	Seed      int64                  `protobuf:"varint,10,opt,name=seed,proto3" json:"seed,omitempty"`      // the map and the game are reproduced from it
+	MapName   string                 `protobuf:"bytes,11,opt,name=mapName,proto3" json:"mapName,omitempty"` // hand-authored map the session is played on, empty for a generated one
 }
 
 func (x *Session) Reset() {
@@ -523,7 +582,42 @@ func (x *Session) GetStartTime() *timestamppb.Timestamp {
 	return nil
 }
 
-type UserId struct {
+func (x *Session) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Session) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Session) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Session) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Session) GetMapName() string {
+	if x != nil {
+		return x.MapName
+	}
+	return ""
+}
+
+type SessionId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -531,8 +625,8 @@ type UserId struct {
 	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *UserId) Reset() {
-	*x = UserId{}
+func (x *SessionId) Reset() {
+	*x = SessionId{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -540,13 +634,13 @@ func (x *UserId) Reset() {
 	}
 }
 
-func (x *UserId) String() string {
+func (x *SessionId) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserId) ProtoMessage() {}
+func (*SessionId) ProtoMessage() {}
 
-func (x *UserId) ProtoReflect() protoreflect.Message {
+func (x *SessionId) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -558,28 +652,29 @@ func (x *UserId) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserId.ProtoReflect.Descriptor instead.
-func (*UserId) Descriptor() ([]byte, []int) {
+// Deprecated: Use SessionId.ProtoReflect.Descriptor instead.
+func (*SessionId) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *UserId) GetId() int32 {
+func (x *SessionId) GetId() int32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type SessionId struct {
+type Credentials struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int32 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 }
 
-func (x *SessionId) Reset() {
-	*x = SessionId{}
+func (x *Credentials) Reset() {
+	*x = Credentials{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -587,13 +682,13 @@ func (x *SessionId) Reset() {
 	}
 }
 
-func (x *SessionId) String() string {
+func (x *Credentials) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionId) ProtoMessage() {}
+func (*Credentials) ProtoMessage() {}
 
-func (x *SessionId) ProtoReflect() protoreflect.Message {
+func (x *Credentials) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -605,29 +700,39 @@ func (x *SessionId) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionId.ProtoReflect.Descriptor instead.
-func (*SessionId) Descriptor() ([]byte, []int) {
+// Deprecated: Use Credentials.ProtoReflect.Descriptor instead.
+func (*Credentials) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SessionId) GetId() int32 {
+func (x *Credentials) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-type Event struct {
+func (x *Credentials) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Token must be sent in "authorization: Bearer <token>" metadata of every rpc
+// except Register and Login; the player is identified by it
+type AuthToken struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type string          `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Area []*Coordintates `protobuf:"bytes,2,rep,name=area,proto3" json:"area,omitempty"`
+	Token     string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	UserId    int32                  `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 }
 
-func (x *Event) Reset() {
-	*x = Event{}
+func (x *AuthToken) Reset() {
+	*x = AuthToken{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -635,13 +740,13 @@ func (x *Event) Reset() {
 	}
 }
 
-func (x *Event) String() string {
+func (x *AuthToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event) ProtoMessage() {}
+func (*AuthToken) ProtoMessage() {}
 
-func (x *Event) ProtoReflect() protoreflect.Message {
+func (x *AuthToken) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -653,35 +758,44 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event.ProtoReflect.Descriptor instead.
-func (*Event) Descriptor() ([]byte, []int) {
+// Deprecated: Use AuthToken.ProtoReflect.Descriptor instead.
+func (*AuthToken) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Event) GetType() string {
+func (x *AuthToken) GetToken() string {
 	if x != nil {
-		return x.Type
+		return x.Token
 	}
 	return ""
 }
 
-func (x *Event) GetArea() []*Coordintates {
+func (x *AuthToken) GetUserId() int32 {
 	if x != nil {
-		return x.Area
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AuthToken) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
 	}
 	return nil
 }
 
-type Path struct {
+type GetSessionReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Points []*Coordintates `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	Mode string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`  // used when a new session is created, empty for the default mode
+	Seed int64  `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"` // a new session is created from it, 0 for a random one
+	Map  string `protobuf:"bytes,4,opt,name=map,proto3" json:"map,omitempty"`    // hand-authored map of a new session, empty for a generated one
 }
 
-func (x *Path) Reset() {
-	*x = Path{}
+func (x *GetSessionReq) Reset() {
+	*x = GetSessionReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -689,13 +803,13 @@ func (x *Path) Reset() {
 	}
 }
 
-func (x *Path) String() string {
+func (x *GetSessionReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Path) ProtoMessage() {}
+func (*GetSessionReq) ProtoMessage() {}
 
-func (x *Path) ProtoReflect() protoreflect.Message {
+func (x *GetSessionReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -707,29 +821,43 @@ func (x *Path) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Path.ProtoReflect.Descriptor instead.
-func (*Path) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetSessionReq.ProtoReflect.Descriptor instead.
+func (*GetSessionReq) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Path) GetPoints() []*Coordintates {
+func (x *GetSessionReq) GetMode() string {
 	if x != nil {
-		return x.Points
+		return x.Mode
 	}
-	return nil
+	return ""
 }
 
-type OutNetworkPassenger struct {
+func (x *GetSessionReq) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *GetSessionReq) GetMap() string {
+	if x != nil {
+		return x.Map
+	}
+	return ""
+}
+
+type SetupReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Position   *Coordintates          `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
-	TimeToBurn *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timeToBurn,proto3" json:"timeToBurn,omitempty"`
+	SessionId int32  `protobuf:"varint,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // rules of the session if set
+	Mode      string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`            // rules of the mode otherwise, empty for the default mode
 }
 
-func (x *OutNetworkPassenger) Reset() {
-	*x = OutNetworkPassenger{}
+func (x *SetupReq) Reset() {
+	*x = SetupReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -737,13 +865,13 @@ func (x *OutNetworkPassenger) Reset() {
 	}
 }
 
-func (x *OutNetworkPassenger) String() string {
+func (x *SetupReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OutNetworkPassenger) ProtoMessage() {}
+func (*SetupReq) ProtoMessage() {}
 
-func (x *OutNetworkPassenger) ProtoReflect() protoreflect.Message {
+func (x *SetupReq) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -755,39 +883,36 @@ func (x *OutNetworkPassenger) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OutNetworkPassenger.ProtoReflect.Descriptor instead.
-func (*OutNetworkPassenger) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetupReq.ProtoReflect.Descriptor instead.
+func (*SetupReq) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *OutNetworkPassenger) GetPosition() *Coordintates {
+func (x *SetupReq) GetSessionId() int32 {
 	if x != nil {
-		return x.Position
+		return x.SessionId
 	}
-	return nil
+	return 0
 }
 
-func (x *OutNetworkPassenger) GetTimeToBurn() *timestamppb.Timestamp {
+func (x *SetupReq) GetMode() string {
 	if x != nil {
-		return x.TimeToBurn
+		return x.Mode
 	}
-	return nil
+	return ""
 }
 
-type State struct {
+type Event struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Users                []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	ChangedBlocks        []*Block               `protobuf:"bytes,2,rep,name=changedBlocks,proto3" json:"changedBlocks,omitempty"`
-	NewEvents            []*Event               `protobuf:"bytes,3,rep,name=newEvents,proto3" json:"newEvents,omitempty"`
-	Tracks               []*Path                `protobuf:"bytes,4,rep,name=tracks,proto3" json:"tracks,omitempty"`
-	OutNetworkPassengers []*OutNetworkPassenger `protobuf:"bytes,5,rep,name=outNetworkPassengers,proto3" json:"outNetworkPassengers,omitempty"`
+	Type string          `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Area []*Coordintates `protobuf:"bytes,2,rep,name=area,proto3" json:"area,omitempty"`
 }
 
-func (x *State) Reset() {
-	*x = State{}
+func (x *Event) Reset() {
+	*x = Event{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -795,13 +920,13 @@ func (x *State) Reset() {
 	}
 }
 
-func (x *State) String() string {
+func (x *Event) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*State) ProtoMessage() {}
+func (*Event) ProtoMessage() {}
 
-func (x *State) ProtoReflect() protoreflect.Message {
+func (x *Event) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -813,59 +938,38 @@ func (x *State) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use State.ProtoReflect.Descriptor instead.
-func (*State) Descriptor() ([]byte, []int) {
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *State) GetUsers() []*User {
-	if x != nil {
-		return x.Users
-	}
-	return nil
-}
-
-func (x *State) GetChangedBlocks() []*Block {
-	if x != nil {
-		return x.ChangedBlocks
-	}
-	return nil
-}
-
-func (x *State) GetNewEvents() []*Event {
-	if x != nil {
-		return x.NewEvents
-	}
-	return nil
-}
-
-func (x *State) GetTracks() []*Path {
+func (x *Event) GetType() string {
 	if x != nil {
-		return x.Tracks
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-func (x *State) GetOutNetworkPassengers() []*OutNetworkPassenger {
+func (x *Event) GetArea() []*Coordintates {
 	if x != nil {
-		return x.OutNetworkPassengers
+		return x.Area
 	}
 	return nil
 }
 
-type NewTransportReq struct {
+type Path struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId    int32         `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	From      *Coordintates `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
-	To        *Coordintates `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
-	Transport Transport     `protobuf:"varint,4,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"`
+	Points      []*Coordintates        `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	StartTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	ArrivalTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=arrivalTime,proto3" json:"arrivalTime,omitempty"`
+	PassengerId int64                  `protobuf:"varint,4,opt,name=passengerId,proto3" json:"passengerId,omitempty"` // the passenger who travels the track. A track with the id
 }
 
-func (x *NewTransportReq) Reset() {
-	*x = NewTransportReq{}
+func (x *Path) Reset() {
+	*x = Path{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -873,13 +977,13 @@ func (x *NewTransportReq) Reset() {
 	}
 }
 
-func (x *NewTransportReq) String() string {
+func (x *Path) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewTransportReq) ProtoMessage() {}
+func (*Path) ProtoMessage() {}
 
-func (x *NewTransportReq) ProtoReflect() protoreflect.Message {
+func (x *Path) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -891,50 +995,55 @@ func (x *NewTransportReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewTransportReq.ProtoReflect.Descriptor instead.
-func (*NewTransportReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NewTransportReq) GetUserId() int32 {
+func (x *Path) GetPoints() []*Coordintates {
 	if x != nil {
-		return x.UserId
+		return x.Points
 	}
-	return 0
+	return nil
 }
 
-func (x *NewTransportReq) GetFrom() *Coordintates {
+func (x *Path) GetStartTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.From
+		return x.StartTime
 	}
 	return nil
 }
 
-func (x *NewTransportReq) GetTo() *Coordintates {
+func (x *Path) GetArrivalTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.To
+		return x.ArrivalTime
 	}
 	return nil
 }
 
-func (x *NewTransportReq) GetTransport() Transport {
+func (x *Path) GetPassengerId() int64 {
 	if x != nil {
-		return x.Transport
+		return x.PassengerId
 	}
-	return Transport_BUS
+	return 0
 }
 
-type ExtendLicenseReq struct {
+// Passenger is a traveller on the way, between the blocks of the current hop
+type Passenger struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId int32           `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	Blocks []*Coordintates `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"` // new blocks in license
+	Id        int64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	From      *Coordintates `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To        *Coordintates `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Progress  float32       `protobuf:"fixed32,4,opt,name=progress,proto3" json:"progress,omitempty"` // share of the hop passed, from 0 to 1
+	Transport Transport     `protobuf:"varint,5,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"`
+	OwnerId   int32         `protobuf:"varint,6,opt,name=ownerId,proto3" json:"ownerId,omitempty"` // user who built the transport of the hop
 }
 
-func (x *ExtendLicenseReq) Reset() {
-	*x = ExtendLicenseReq{}
+func (x *Passenger) Reset() {
+	*x = Passenger{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_v1_server_api_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -942,13 +1051,13 @@ func (x *ExtendLicenseReq) Reset() {
 	}
 }
 
-func (x *ExtendLicenseReq) String() string {
+func (x *Passenger) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExtendLicenseReq) ProtoMessage() {}
+func (*Passenger) ProtoMessage() {}
 
-func (x *ExtendLicenseReq) ProtoReflect() protoreflect.Message {
+func (x *Passenger) ProtoReflect() protoreflect.Message {
 	mi := &file_api_v1_server_api_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -960,38 +1069,524 @@ func (x *ExtendLicenseReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExtendLicenseReq.ProtoReflect.Descriptor instead.
-func (*ExtendLicenseReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use Passenger.ProtoReflect.Descriptor instead.
+func (*Passenger) Descriptor() ([]byte, []int) {
 	return file_api_v1_server_api_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ExtendLicenseReq) GetUserId() int32 {
+func (x *Passenger) GetId() int64 {
 	if x != nil {
-		return x.UserId
+		return x.Id
 	}
 	return 0
 }
 
-func (x *ExtendLicenseReq) GetBlocks() []*Coordintates {
+func (x *Passenger) GetFrom() *Coordintates {
 	if x != nil {
-		return x.Blocks
+		return x.From
 	}
 	return nil
 }
 
-type StateStreamReq struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	SessionId *SessionId `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
-	UserId    *UserId    `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
-}
+func (x *Passenger) GetTo() *Coordintates {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Passenger) GetProgress() float32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *Passenger) GetTransport() Transport {
+	if x != nil {
+		return x.Transport
+	}
+	return Transport_BUS
+}
+
+func (x *Passenger) GetOwnerId() int32 {
+	if x != nil {
+		return x.OwnerId
+	}
+	return 0
+}
+
+// PendingReward is the money the user gets when the passengers on the way pass the user's transport
+type PendingReward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId int32 `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Money  int32 `protobuf:"varint,2,opt,name=money,proto3" json:"money,omitempty"`
+}
+
+func (x *PendingReward) Reset() {
+	*x = PendingReward{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PendingReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingReward) ProtoMessage() {}
+
+func (x *PendingReward) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingReward.ProtoReflect.Descriptor instead.
+func (*PendingReward) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PendingReward) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PendingReward) GetMoney() int32 {
+	if x != nil {
+		return x.Money
+	}
+	return 0
+}
+
+type OutNetworkPassenger struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Position   *Coordintates          `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	TimeToBurn *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timeToBurn,proto3" json:"timeToBurn,omitempty"`
+}
+
+func (x *OutNetworkPassenger) Reset() {
+	*x = OutNetworkPassenger{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutNetworkPassenger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutNetworkPassenger) ProtoMessage() {}
+
+func (x *OutNetworkPassenger) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutNetworkPassenger.ProtoReflect.Descriptor instead.
+func (*OutNetworkPassenger) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OutNetworkPassenger) GetPosition() *Coordintates {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *OutNetworkPassenger) GetTimeToBurn() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TimeToBurn
+	}
+	return nil
+}
+
+type State struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users                []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	ChangedBlocks        []*Block               `protobuf:"bytes,2,rep,name=changedBlocks,proto3" json:"changedBlocks,omitempty"`
+	NewEvents            []*Event               `protobuf:"bytes,3,rep,name=newEvents,proto3" json:"newEvents,omitempty"`
+	Tracks               []*Path                `protobuf:"bytes,4,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	OutNetworkPassengers []*OutNetworkPassenger `protobuf:"bytes,5,rep,name=outNetworkPassengers,proto3" json:"outNetworkPassengers,omitempty"`
+	Seq                  int64                  `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"` // grows by one every tick
+	Kind                 StateKind              `protobuf:"varint,7,opt,name=kind,proto3,enum=StateKind" json:"kind,omitempty"`
+	TimeLeft             *durationpb.Duration   `protobuf:"bytes,8,opt,name=timeLeft,proto3" json:"timeLeft,omitempty"`              // until the session finishes
+	Passengers           []*Passenger           `protobuf:"bytes,9,rep,name=passengers,proto3" json:"passengers,omitempty"`          // every traveller on the way at the moment of the state
+	PendingRewards       []*PendingReward       `protobuf:"bytes,10,rep,name=pendingRewards,proto3" json:"pendingRewards,omitempty"` // of every user who is still to get money
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *State) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *State) GetChangedBlocks() []*Block {
+	if x != nil {
+		return x.ChangedBlocks
+	}
+	return nil
+}
+
+func (x *State) GetNewEvents() []*Event {
+	if x != nil {
+		return x.NewEvents
+	}
+	return nil
+}
+
+func (x *State) GetTracks() []*Path {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *State) GetOutNetworkPassengers() []*OutNetworkPassenger {
+	if x != nil {
+		return x.OutNetworkPassengers
+	}
+	return nil
+}
+
+func (x *State) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *State) GetKind() StateKind {
+	if x != nil {
+		return x.Kind
+	}
+	return StateKind_DELTA
+}
+
+func (x *State) GetTimeLeft() *durationpb.Duration {
+	if x != nil {
+		return x.TimeLeft
+	}
+	return nil
+}
+
+func (x *State) GetPassengers() []*Passenger {
+	if x != nil {
+		return x.Passengers
+	}
+	return nil
+}
+
+func (x *State) GetPendingRewards() []*PendingReward {
+	if x != nil {
+		return x.PendingRewards
+	}
+	return nil
+}
+
+type NewTransportReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From      *Coordintates `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To        *Coordintates `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Transport Transport     `protobuf:"varint,4,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"`
+}
+
+func (x *NewTransportReq) Reset() {
+	*x = NewTransportReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewTransportReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewTransportReq) ProtoMessage() {}
+
+func (x *NewTransportReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewTransportReq.ProtoReflect.Descriptor instead.
+func (*NewTransportReq) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NewTransportReq) GetFrom() *Coordintates {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *NewTransportReq) GetTo() *Coordintates {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *NewTransportReq) GetTransport() Transport {
+	if x != nil {
+		return x.Transport
+	}
+	return Transport_BUS
+}
+
+type RemoveTransportReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From *Coordintates `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   *Coordintates `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *RemoveTransportReq) Reset() {
+	*x = RemoveTransportReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveTransportReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTransportReq) ProtoMessage() {}
+
+func (x *RemoveTransportReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTransportReq.ProtoReflect.Descriptor instead.
+func (*RemoveTransportReq) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveTransportReq) GetFrom() *Coordintates {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *RemoveTransportReq) GetTo() *Coordintates {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type UpgradeTransportReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From      *Coordintates `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To        *Coordintates `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Transport Transport     `protobuf:"varint,4,opt,name=transport,proto3,enum=Transport" json:"transport,omitempty"` // the new transport of the route
+}
+
+func (x *UpgradeTransportReq) Reset() {
+	*x = UpgradeTransportReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpgradeTransportReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpgradeTransportReq) ProtoMessage() {}
+
+func (x *UpgradeTransportReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpgradeTransportReq.ProtoReflect.Descriptor instead.
+func (*UpgradeTransportReq) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpgradeTransportReq) GetFrom() *Coordintates {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *UpgradeTransportReq) GetTo() *Coordintates {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *UpgradeTransportReq) GetTransport() Transport {
+	if x != nil {
+		return x.Transport
+	}
+	return Transport_BUS
+}
+
+type ExtendLicenseReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blocks []*Coordintates `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"` // new blocks in license
+}
+
+func (x *ExtendLicenseReq) Reset() {
+	*x = ExtendLicenseReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_server_api_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendLicenseReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendLicenseReq) ProtoMessage() {}
+
+func (x *ExtendLicenseReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_server_api_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendLicenseReq.ProtoReflect.Descriptor instead.
+func (*ExtendLicenseReq) Descriptor() ([]byte, []int) {
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ExtendLicenseReq) GetBlocks() []*Coordintates {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type StateStreamReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId *SessionId `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	LastSeq   int64      `protobuf:"varint,3,opt,name=lastSeq,proto3" json:"lastSeq,omitempty"` // seq of the last state received before reconnect, 0 if none
+}
 
 func (x *StateStreamReq) Reset() {
 	*x = StateStreamReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_v1_server_api_proto_msgTypes[13]
+		mi := &file_api_v1_server_api_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1004,7 +1599,7 @@ func (x *StateStreamReq) String() string {
 func (*StateStreamReq) ProtoMessage() {}
 
 func (x *StateStreamReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_server_api_proto_msgTypes[13]
+	mi := &file_api_v1_server_api_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1017,7 +1612,7 @@ func (x *StateStreamReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StateStreamReq.ProtoReflect.Descriptor instead.
 func (*StateStreamReq) Descriptor() ([]byte, []int) {
-	return file_api_v1_server_api_proto_rawDescGZIP(), []int{13}
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *StateStreamReq) GetSessionId() *SessionId {
@@ -1027,11 +1622,11 @@ func (x *StateStreamReq) GetSessionId() *SessionId {
 	return nil
 }
 
-func (x *StateStreamReq) GetUserId() *UserId {
+func (x *StateStreamReq) GetLastSeq() int64 {
 	if x != nil {
-		return x.UserId
+		return x.LastSeq
 	}
-	return nil
+	return 0
 }
 
 type Setup struct {
@@ -1051,12 +1646,26 @@ type Setup struct {
 	DurationMetro *durationpb.Duration `protobuf:"bytes,9,opt,name=DurationMetro,proto3" json:"DurationMetro,omitempty"`
 	DurationTaxi  *durationpb.Duration `protobuf:"bytes,10,opt,name=DurationTaxi,proto3" json:"DurationTaxi,omitempty"`
 	DurationTram  *durationpb.Duration `protobuf:"bytes,11,opt,name=DurationTram,proto3" json:"DurationTram,omitempty"`
+	MaxPlayers    int32                `protobuf:"varint,12,opt,name=maxPlayers,proto3" json:"maxPlayers,omitempty"`
+	// Deprecated: Marked as deprecated in api/v1/server-api.proto.
+	SideLen            int32  `protobuf:"varint,13,opt,name=sideLen,proto3" json:"sideLen,omitempty"` // the map width, use width and height
+	LicenseAreaSideLen int32  `protobuf:"varint,14,opt,name=licenseAreaSideLen,proto3" json:"licenseAreaSideLen,omitempty"`
+	StartMoney         int32  `protobuf:"varint,15,opt,name=startMoney,proto3" json:"startMoney,omitempty"`
+	RewardBus          int32  `protobuf:"varint,16,opt,name=RewardBus,proto3" json:"RewardBus,omitempty"`
+	RewardMetro        int32  `protobuf:"varint,17,opt,name=RewardMetro,proto3" json:"RewardMetro,omitempty"`
+	RewardTaxi         int32  `protobuf:"varint,18,opt,name=RewardTaxi,proto3" json:"RewardTaxi,omitempty"`
+	RewardTram         int32  `protobuf:"varint,19,opt,name=RewardTram,proto3" json:"RewardTram,omitempty"`
+	Mode               string `protobuf:"bytes,20,opt,name=mode,proto3" json:"mode,omitempty"`
+	UnlimitedMoney     bool   `protobuf:"varint,21,opt,name=unlimitedMoney,proto3" json:"unlimitedMoney,omitempty"`
+	BuildPolicy        string `protobuf:"bytes,22,opt,name=buildPolicy,proto3" json:"buildPolicy,omitempty"` // "both": routes only within the license, "one": one route endpoint must be licensed
+	Width              int32  `protobuf:"varint,23,opt,name=width,proto3" json:"width,omitempty"`
+	Height             int32  `protobuf:"varint,24,opt,name=height,proto3" json:"height,omitempty"`
 }
 
 func (x *Setup) Reset() {
 	*x = Setup{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_v1_server_api_proto_msgTypes[14]
+		mi := &file_api_v1_server_api_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1069,7 +1678,7 @@ func (x *Setup) String() string {
 func (*Setup) ProtoMessage() {}
 
 func (x *Setup) ProtoReflect() protoreflect.Message {
-	mi := &file_api_v1_server_api_proto_msgTypes[14]
+	mi := &file_api_v1_server_api_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1082,7 +1691,7 @@ func (x *Setup) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Setup.ProtoReflect.Descriptor instead.
 func (*Setup) Descriptor() ([]byte, []int) {
-	return file_api_v1_server_api_proto_rawDescGZIP(), []int{14}
+	return file_api_v1_server_api_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *Setup) GetTimeLimitMin() int32 {
@@ -1162,6 +1771,98 @@ func (x *Setup) GetDurationTram() *durationpb.Duration {
 	return nil
 }
 
+func (x *Setup) GetMaxPlayers() int32 {
+	if x != nil {
+		return x.MaxPlayers
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in api/v1/server-api.proto.
+func (x *Setup) GetSideLen() int32 {
+	if x != nil {
+		return x.SideLen
+	}
+	return 0
+}
+
+func (x *Setup) GetLicenseAreaSideLen() int32 {
+	if x != nil {
+		return x.LicenseAreaSideLen
+	}
+	return 0
+}
+
+func (x *Setup) GetStartMoney() int32 {
+	if x != nil {
+		return x.StartMoney
+	}
+	return 0
+}
+
+func (x *Setup) GetRewardBus() int32 {
+	if x != nil {
+		return x.RewardBus
+	}
+	return 0
+}
+
+func (x *Setup) GetRewardMetro() int32 {
+	if x != nil {
+		return x.RewardMetro
+	}
+	return 0
+}
+
+func (x *Setup) GetRewardTaxi() int32 {
+	if x != nil {
+		return x.RewardTaxi
+	}
+	return 0
+}
+
+func (x *Setup) GetRewardTram() int32 {
+	if x != nil {
+		return x.RewardTram
+	}
+	return 0
+}
+
+func (x *Setup) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Setup) GetUnlimitedMoney() bool {
+	if x != nil {
+		return x.UnlimitedMoney
+	}
+	return false
+}
+
+func (x *Setup) GetBuildPolicy() string {
+	if x != nil {
+		return x.BuildPolicy
+	}
+	return ""
+}
+
+func (x *Setup) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Setup) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 var File_api_v1_server_api_proto protoreflect.FileDescriptor
 
 var file_api_v1_server_api_proto_rawDesc = []byte{
@@ -1181,123 +1882,229 @@ var file_api_v1_server_api_proto_rawDesc = []byte{
 	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
 	0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x07, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65,
 	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e,
-	0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x07, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x22, 0x7e,
-	0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
-	0x72, 0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2f, 0x0a,
-	0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65,
-	0x73, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9a,
-	0x01, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x29, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f,
-	0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x0a, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12,
-	0x2a, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52,
-	0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x22, 0xeb, 0x01, 0x0a, 0x07,
-	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x05, 0x75,
-	0x73, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x06, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x37,
-	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x74, 0x69,
-	0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x18, 0x0a, 0x06, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x02, 0x69, 0x64, 0x22, 0x1b, 0x0a, 0x09, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0x3e, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a,
-	0x04, 0x61, 0x72, 0x65, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f,
-	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61,
-	0x22, 0x2d, 0x0a, 0x04, 0x50, 0x61, 0x74, 0x68, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64,
-	0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22,
-	0x7c, 0x0a, 0x13, 0x4f, 0x75, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73,
-	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64,
-	0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x3a, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x6f, 0x42, 0x75, 0x72, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x07, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x22, 0xc0,
+	0x01, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2f,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x40, 0x0a, 0x0d, 0x69, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0d, 0x69, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x46, 0x72, 0x6f,
+	0x6d, 0x22, 0x9a, 0x01, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x29, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x12, 0x2a, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x22, 0xdb,
+	0x02, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x05, 0x75, 0x73,
+	0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x03, 0x6d, 0x61,
+	0x70, 0x12, 0x37, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x6f, 0x42, 0x75, 0x72, 0x6e, 0x22, 0xe1, 0x01,
-	0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x05, 0x75,
-	0x73, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x73, 0x12, 0x24, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x6e,
-	0x65, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x63,
-	0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
-	0x06, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x12, 0x48, 0x0a, 0x14, 0x6f, 0x75, 0x74, 0x4e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x18,
-	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x4f, 0x75, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x52, 0x14, 0x6f, 0x75, 0x74,
-	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72,
-	0x73, 0x22, 0x95, 0x01, 0x0a, 0x0f, 0x4e, 0x65, 0x77, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
-	0x72, 0x74, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x21, 0x0a,
-	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f,
-	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d,
-	0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43,
-	0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x02, 0x74, 0x6f, 0x12,
-	0x28, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x09,
-	0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x51, 0x0a, 0x10, 0x45, 0x78, 0x74,
-	0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74,
-	0x61, 0x74, 0x65, 0x73, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x5b, 0x0a, 0x0e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x12, 0x28,
-	0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x52, 0x09, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49,
-	0x64, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0xd9, 0x03, 0x0a, 0x05, 0x53, 0x65,
-	0x74, 0x75, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
-	0x4d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x4c,
-	0x69, 0x6d, 0x69, 0x74, 0x4d, 0x69, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x69, 0x63, 0x65, 0x6e,
-	0x73, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6c, 0x69,
-	0x63, 0x65, 0x6e, 0x73, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x6e, 0x70,
-	0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6f,
-	0x6e, 0x70, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x73,
-	0x74, 0x42, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x43, 0x6f, 0x73, 0x74,
-	0x42, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x6f,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x43, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x72,
-	0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x61, 0x78, 0x69, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x61, 0x78, 0x69, 0x12, 0x1a, 0x0a,
-	0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6d, 0x12, 0x3b, 0x0a, 0x0b, 0x44, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x44, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x42, 0x75, 0x73, 0x12, 0x3f, 0x0a, 0x0d, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x12, 0x3d, 0x0a, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x54, 0x61, 0x78, 0x69, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x54, 0x61, 0x78, 0x69, 0x12, 0x3d, 0x0a, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6d, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
-	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x54, 0x72, 0x61, 0x6d, 0x2a, 0x4e, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x79,
+	0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65,
+	0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x1b, 0x0a, 0x09,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x0b, 0x43, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x73, 0x0a, 0x09, 0x41, 0x75, 0x74, 0x68,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x4f, 0x0a,
+	0x0d, 0x47, 0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x12,
+	0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x3c,
+	0x0a, 0x08, 0x53, 0x65, 0x74, 0x75, 0x70, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x3e, 0x0a, 0x05,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x61, 0x72, 0x65,
+	0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x61, 0x72, 0x65, 0x61, 0x22, 0xc7, 0x01, 0x0a,
+	0x04, 0x50, 0x61, 0x74, 0x68, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74,
+	0x61, 0x74, 0x65, 0x73, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x09,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x61, 0x72, 0x72, 0x69, 0x76, 0x61,
+	0x6c, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x61, 0x72, 0x72, 0x69, 0x76, 0x61, 0x6c,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65,
+	0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x70, 0x61, 0x73, 0x73, 0x65,
+	0x6e, 0x67, 0x65, 0x72, 0x49, 0x64, 0x22, 0xbd, 0x01, 0x0a, 0x09, 0x50, 0x61, 0x73, 0x73, 0x65,
+	0x6e, 0x67, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65,
+	0x73, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x28, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6f,
+	0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x22, 0x3d, 0x0a, 0x0d, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x22, 0x7c, 0x0a, 0x13, 0x4f, 0x75, 0x74, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x54,
+	0x6f, 0x42, 0x75, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x54, 0x6f, 0x42,
+	0x75, 0x72, 0x6e, 0x22, 0xae, 0x03, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a,
+	0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x06, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x24, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x06, 0x2e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1d,
+	0x0a, 0x06, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05,
+	0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x06, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x12, 0x48, 0x0a,
+	0x14, 0x6f, 0x75, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65,
+	0x6e, 0x67, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x4f, 0x75,
+	0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65,
+	0x72, 0x52, 0x14, 0x6f, 0x75, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x61, 0x73,
+	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x1e, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4b,
+	0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x35, 0x0a, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x4c, 0x65, 0x66, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x65, 0x66, 0x74,
+	0x12, 0x2a, 0x0a, 0x0a, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72,
+	0x52, 0x0a, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12, 0x36, 0x0a, 0x0e,
+	0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x52, 0x0e, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x0f, 0x4e, 0x65, 0x77, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x28, 0x0a, 0x09, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x70, 0x6f, 0x72, 0x74, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x5c, 0x0a, 0x12, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x02,
+	0x74, 0x6f, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x87, 0x01, 0x0a, 0x13, 0x55, 0x70, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x12, 0x21, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x28, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x4a, 0x04, 0x08, 0x01,
+	0x10, 0x02, 0x22, 0x3f, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65,
+	0x6e, 0x73, 0x65, 0x52, 0x65, 0x71, 0x12, 0x25, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x4a, 0x04, 0x08,
+	0x01, 0x10, 0x02, 0x22, 0x5a, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x12, 0x28, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x71, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x22,
+	0xf3, 0x06, 0x0a, 0x05, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x69, 0x6d,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0c, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x69, 0x6e, 0x12, 0x20, 0x0a,
+	0x0b, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0b, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x43, 0x6f, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x6f, 0x6e, 0x70, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x6f, 0x6e, 0x70, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x43, 0x6f, 0x73, 0x74, 0x42, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x43, 0x6f, 0x73, 0x74, 0x42, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6f, 0x73,
+	0x74, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x43, 0x6f,
+	0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54,
+	0x61, 0x78, 0x69, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54,
+	0x61, 0x78, 0x69, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6d, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6d, 0x12,
+	0x3b, 0x0a, 0x0b, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0b, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x75, 0x73, 0x12, 0x3f, 0x0a, 0x0d,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x12, 0x3d, 0x0a,
+	0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x78, 0x69, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x78, 0x69, 0x12, 0x3d, 0x0a, 0x0c,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6d, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x6d,
+	0x61, 0x78, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x6d, 0x61, 0x78, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x07, 0x73,
+	0x69, 0x64, 0x65, 0x4c, 0x65, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x42, 0x02, 0x18, 0x01,
+	0x52, 0x07, 0x73, 0x69, 0x64, 0x65, 0x4c, 0x65, 0x6e, 0x12, 0x2e, 0x0a, 0x12, 0x6c, 0x69, 0x63,
+	0x65, 0x6e, 0x73, 0x65, 0x41, 0x72, 0x65, 0x61, 0x53, 0x69, 0x64, 0x65, 0x4c, 0x65, 0x6e, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x41, 0x72,
+	0x65, 0x61, 0x53, 0x69, 0x64, 0x65, 0x4c, 0x65, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x42, 0x75, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x52, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x42, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x52, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x74, 0x72, 0x6f, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x54, 0x61, 0x78, 0x69, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x52,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x54, 0x61, 0x78, 0x69, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x54, 0x72, 0x61, 0x6d, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x52,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x54, 0x72, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a,
+	0x0e, 0x75, 0x6e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x75, 0x6e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a,
+	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x2a, 0x4e, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x79,
 	0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x53, 0x49, 0x44, 0x45, 0x4e, 0x54, 0x49, 0x41,
 	0x4c, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x4e, 0x54, 0x45, 0x52, 0x54, 0x41, 0x49, 0x4e,
 	0x4d, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x49, 0x4e, 0x44, 0x55, 0x53, 0x54,
@@ -1309,24 +2116,38 @@ var file_api_v1_server_api_proto_rawDesc = []byte{
 	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x57,
 	0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x43, 0x54, 0x49,
 	0x56, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44,
-	0x10, 0x02, 0x32, 0xf2, 0x01, 0x0a, 0x03, 0x41, 0x70, 0x69, 0x12, 0x1f, 0x0a, 0x0a, 0x47, 0x65,
-	0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49,
-	0x64, 0x1a, 0x08, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x08, 0x47,
-	0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
-	0x06, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x38, 0x0a, 0x0c, 0x4e, 0x65, 0x77, 0x54, 0x72,
-	0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x10, 0x2e, 0x4e, 0x65, 0x77, 0x54, 0x72, 0x61,
-	0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x12, 0x3a, 0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65, 0x6e,
-	0x73, 0x65, 0x12, 0x11, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65, 0x6e,
-	0x73, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x28, 0x0a,
-	0x0b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0f, 0x2e, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x1a, 0x06, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x30, 0x01, 0x42, 0x14, 0x5a, 0x12, 0x67, 0x61, 0x6d, 0x65, 0x5f,
-	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x10, 0x02, 0x2a, 0x24, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x09, 0x0a, 0x05, 0x44, 0x45, 0x4c, 0x54, 0x41, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x4e,
+	0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10, 0x01, 0x32, 0xb7, 0x03, 0x0a, 0x03, 0x41, 0x70, 0x69,
+	0x12, 0x24, 0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x0c, 0x2e, 0x43,
+	0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x1a, 0x0a, 0x2e, 0x41, 0x75, 0x74,
+	0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x21, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12,
+	0x0c, 0x2e, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x1a, 0x0a, 0x2e,
+	0x41, 0x75, 0x74, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x26, 0x0a, 0x0a, 0x47, 0x65, 0x74,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x08, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1d, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x09, 0x2e,
+	0x53, 0x65, 0x74, 0x75, 0x70, 0x52, 0x65, 0x71, 0x1a, 0x06, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70,
+	0x12, 0x38, 0x0a, 0x0c, 0x4e, 0x65, 0x77, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x12, 0x10, 0x2e, 0x4e, 0x65, 0x77, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3e, 0x0a, 0x0f, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x13, 0x2e,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x10, 0x55, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x14,
+	0x2e, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3a, 0x0a, 0x0d,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x12, 0x11, 0x2e,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x4c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x71,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x28, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x1a, 0x06, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x30, 0x01, 0x42, 0x14, 0x5a, 0x12, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1341,77 +2162,108 @@ func file_api_v1_server_api_proto_rawDescGZIP() []byte {
 	return file_api_v1_server_api_proto_rawDescData
 }
 
-var file_api_v1_server_api_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_api_v1_server_api_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_api_v1_server_api_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_api_v1_server_api_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_api_v1_server_api_proto_goTypes = []interface{}{
 	(BlockType)(0),                // 0: BlockType
 	(Transport)(0),                // 1: Transport
 	(SessionStatus)(0),            // 2: SessionStatus
-	(*Coordintates)(nil),          // 3: Coordintates
-	(*User)(nil),                  // 4: User
-	(*Connector)(nil),             // 5: Connector
-	(*Block)(nil),                 // 6: Block
-	(*Session)(nil),               // 7: Session
-	(*UserId)(nil),                // 8: UserId
+	(StateKind)(0),                // 3: StateKind
+	(*Coordintates)(nil),          // 4: Coordintates
+	(*User)(nil),                  // 5: User
+	(*Connector)(nil),             // 6: Connector
+	(*Block)(nil),                 // 7: Block
+	(*Session)(nil),               // 8: Session
 	(*SessionId)(nil),             // 9: SessionId
-	(*Event)(nil),                 // 10: Event
-	(*Path)(nil),                  // 11: Path
-	(*OutNetworkPassenger)(nil),   // 12: OutNetworkPassenger
-	(*State)(nil),                 // 13: State
-	(*NewTransportReq)(nil),       // 14: NewTransportReq
-	(*ExtendLicenseReq)(nil),      // 15: ExtendLicenseReq
-	(*StateStreamReq)(nil),        // 16: StateStreamReq
-	(*Setup)(nil),                 // 17: Setup
-	(*durationpb.Duration)(nil),   // 18: google.protobuf.Duration
-	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 20: google.protobuf.Empty
+	(*Credentials)(nil),           // 10: Credentials
+	(*AuthToken)(nil),             // 11: AuthToken
+	(*GetSessionReq)(nil),         // 12: GetSessionReq
+	(*SetupReq)(nil),              // 13: SetupReq
+	(*Event)(nil),                 // 14: Event
+	(*Path)(nil),                  // 15: Path
+	(*Passenger)(nil),             // 16: Passenger
+	(*PendingReward)(nil),         // 17: PendingReward
+	(*OutNetworkPassenger)(nil),   // 18: OutNetworkPassenger
+	(*State)(nil),                 // 19: State
+	(*NewTransportReq)(nil),       // 20: NewTransportReq
+	(*RemoveTransportReq)(nil),    // 21: RemoveTransportReq
+	(*UpgradeTransportReq)(nil),   // 22: UpgradeTransportReq
+	(*ExtendLicenseReq)(nil),      // 23: ExtendLicenseReq
+	(*StateStreamReq)(nil),        // 24: StateStreamReq
+	(*Setup)(nil),                 // 25: Setup
+	(*timestamppb.Timestamp)(nil), // 26: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 27: google.protobuf.Duration
+	(*emptypb.Empty)(nil),         // 28: google.protobuf.Empty
 }
 var file_api_v1_server_api_proto_depIdxs = []int32{
-	3,  // 0: User.license:type_name -> Coordintates
+	4,  // 0: User.license:type_name -> Coordintates
 	1,  // 1: Connector.transport:type_name -> Transport
-	3,  // 2: Connector.destination:type_name -> Coordintates
-	3,  // 3: Block.position:type_name -> Coordintates
-	0,  // 4: Block.type:type_name -> BlockType
-	5,  // 5: Block.connectors:type_name -> Connector
-	4,  // 6: Session.users:type_name -> User
-	6,  // 7: Session.map:type_name -> Block
-	18, // 8: Session.timeLimit:type_name -> google.protobuf.Duration
-	2,  // 9: Session.status:type_name -> SessionStatus
-	19, // 10: Session.startTime:type_name -> google.protobuf.Timestamp
-	3,  // 11: Event.area:type_name -> Coordintates
-	3,  // 12: Path.points:type_name -> Coordintates
-	3,  // 13: OutNetworkPassenger.position:type_name -> Coordintates
-	19, // 14: OutNetworkPassenger.timeToBurn:type_name -> google.protobuf.Timestamp
-	4,  // 15: State.users:type_name -> User
-	6,  // 16: State.changedBlocks:type_name -> Block
-	10, // 17: State.newEvents:type_name -> Event
-	11, // 18: State.tracks:type_name -> Path
-	12, // 19: State.outNetworkPassengers:type_name -> OutNetworkPassenger
-	3,  // 20: NewTransportReq.from:type_name -> Coordintates
-	3,  // 21: NewTransportReq.to:type_name -> Coordintates
-	1,  // 22: NewTransportReq.transport:type_name -> Transport
-	3,  // 23: ExtendLicenseReq.blocks:type_name -> Coordintates
-	9,  // 24: StateStreamReq.sessionId:type_name -> SessionId
-	8,  // 25: StateStreamReq.userId:type_name -> UserId
-	18, // 26: Setup.DurationBus:type_name -> google.protobuf.Duration
-	18, // 27: Setup.DurationMetro:type_name -> google.protobuf.Duration
-	18, // 28: Setup.DurationTaxi:type_name -> google.protobuf.Duration
-	18, // 29: Setup.DurationTram:type_name -> google.protobuf.Duration
-	8,  // 30: Api.GetSession:input_type -> UserId
-	20, // 31: Api.GetSetup:input_type -> google.protobuf.Empty
-	14, // 32: Api.NewTransport:input_type -> NewTransportReq
-	15, // 33: Api.ExtendLicense:input_type -> ExtendLicenseReq
-	16, // 34: Api.StateStream:input_type -> StateStreamReq
-	7,  // 35: Api.GetSession:output_type -> Session
-	17, // 36: Api.GetSetup:output_type -> Setup
-	20, // 37: Api.NewTransport:output_type -> google.protobuf.Empty
-	20, // 38: Api.ExtendLicense:output_type -> google.protobuf.Empty
-	13, // 39: Api.StateStream:output_type -> State
-	35, // [35:40] is the sub-list for method output_type
-	30, // [30:35] is the sub-list for method input_type
-	30, // [30:30] is the sub-list for extension type_name
-	30, // [30:30] is the sub-list for extension extendee
-	0,  // [0:30] is the sub-list for field type_name
+	4,  // 2: Connector.destination:type_name -> Coordintates
+	26, // 3: Connector.inServiceFrom:type_name -> google.protobuf.Timestamp
+	4,  // 4: Block.position:type_name -> Coordintates
+	0,  // 5: Block.type:type_name -> BlockType
+	6,  // 6: Block.connectors:type_name -> Connector
+	5,  // 7: Session.users:type_name -> User
+	7,  // 8: Session.map:type_name -> Block
+	27, // 9: Session.timeLimit:type_name -> google.protobuf.Duration
+	2,  // 10: Session.status:type_name -> SessionStatus
+	26, // 11: Session.startTime:type_name -> google.protobuf.Timestamp
+	26, // 12: AuthToken.expiresAt:type_name -> google.protobuf.Timestamp
+	4,  // 13: Event.area:type_name -> Coordintates
+	4,  // 14: Path.points:type_name -> Coordintates
+	26, // 15: Path.startTime:type_name -> google.protobuf.Timestamp
+	26, // 16: Path.arrivalTime:type_name -> google.protobuf.Timestamp
+	4,  // 17: Passenger.from:type_name -> Coordintates
+	4,  // 18: Passenger.to:type_name -> Coordintates
+	1,  // 19: Passenger.transport:type_name -> Transport
+	4,  // 20: OutNetworkPassenger.position:type_name -> Coordintates
+	26, // 21: OutNetworkPassenger.timeToBurn:type_name -> google.protobuf.Timestamp
+	5,  // 22: State.users:type_name -> User
+	7,  // 23: State.changedBlocks:type_name -> Block
+	14, // 24: State.newEvents:type_name -> Event
+	15, // 25: State.tracks:type_name -> Path
+	18, // 26: State.outNetworkPassengers:type_name -> OutNetworkPassenger
+	3,  // 27: State.kind:type_name -> StateKind
+	27, // 28: State.timeLeft:type_name -> google.protobuf.Duration
+	16, // 29: State.passengers:type_name -> Passenger
+	17, // 30: State.pendingRewards:type_name -> PendingReward
+	4,  // 31: NewTransportReq.from:type_name -> Coordintates
+	4,  // 32: NewTransportReq.to:type_name -> Coordintates
+	1,  // 33: NewTransportReq.transport:type_name -> Transport
+	4,  // 34: RemoveTransportReq.from:type_name -> Coordintates
+	4,  // 35: RemoveTransportReq.to:type_name -> Coordintates
+	4,  // 36: UpgradeTransportReq.from:type_name -> Coordintates
+	4,  // 37: UpgradeTransportReq.to:type_name -> Coordintates
+	1,  // 38: UpgradeTransportReq.transport:type_name -> Transport
+	4,  // 39: ExtendLicenseReq.blocks:type_name -> Coordintates
+	9,  // 40: StateStreamReq.sessionId:type_name -> SessionId
+	27, // 41: Setup.DurationBus:type_name -> google.protobuf.Duration
+	27, // 42: Setup.DurationMetro:type_name -> google.protobuf.Duration
+	27, // 43: Setup.DurationTaxi:type_name -> google.protobuf.Duration
+	27, // 44: Setup.DurationTram:type_name -> google.protobuf.Duration
+	10, // 45: Api.Register:input_type -> Credentials
+	10, // 46: Api.Login:input_type -> Credentials
+	12, // 47: Api.GetSession:input_type -> GetSessionReq
+	13, // 48: Api.GetSetup:input_type -> SetupReq
+	20, // 49: Api.NewTransport:input_type -> NewTransportReq
+	21, // 50: Api.RemoveTransport:input_type -> RemoveTransportReq
+	22, // 51: Api.UpgradeTransport:input_type -> UpgradeTransportReq
+	23, // 52: Api.ExtendLicense:input_type -> ExtendLicenseReq
+	24, // 53: Api.StateStream:input_type -> StateStreamReq
+	11, // 54: Api.Register:output_type -> AuthToken
+	11, // 55: Api.Login:output_type -> AuthToken
+	8,  // 56: Api.GetSession:output_type -> Session
+	25, // 57: Api.GetSetup:output_type -> Setup
+	28, // 58: Api.NewTransport:output_type -> google.protobuf.Empty
+	28, // 59: Api.RemoveTransport:output_type -> google.protobuf.Empty
+	28, // 60: Api.UpgradeTransport:output_type -> google.protobuf.Empty
+	28, // 61: Api.ExtendLicense:output_type -> google.protobuf.Empty
+	19, // 62: Api.StateStream:output_type -> State
+	54, // [54:63] is the sub-list for method output_type
+	45, // [45:54] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_api_v1_server_api_proto_init() }
@@ -1481,7 +2333,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserId); i {
+			switch v := v.(*SessionId); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1493,7 +2345,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionId); i {
+			switch v := v.(*Credentials); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1505,7 +2357,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event); i {
+			switch v := v.(*AuthToken); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1517,7 +2369,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Path); i {
+			switch v := v.(*GetSessionReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1529,7 +2381,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OutNetworkPassenger); i {
+			switch v := v.(*SetupReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1541,7 +2393,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*State); i {
+			switch v := v.(*Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1553,7 +2405,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewTransportReq); i {
+			switch v := v.(*Path); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1565,7 +2417,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExtendLicenseReq); i {
+			switch v := v.(*Passenger); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1577,7 +2429,7 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StateStreamReq); i {
+			switch v := v.(*PendingReward); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1589,6 +2441,90 @@ func file_api_v1_server_api_proto_init() {
 			}
 		}
 		file_api_v1_server_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutNetworkPassenger); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*State); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewTransportReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveTransportReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpgradeTransportReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendLicenseReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateStreamReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_server_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Setup); i {
 			case 0:
 				return &v.state
@@ -1606,8 +2542,8 @@ func file_api_v1_server_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_v1_server_api_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   15,
+			NumEnums:      4,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
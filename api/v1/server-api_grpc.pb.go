@@ -20,20 +20,28 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Api_GetSession_FullMethodName    = "/Api/GetSession"
-	Api_GetSetup_FullMethodName      = "/Api/GetSetup"
-	Api_NewTransport_FullMethodName  = "/Api/NewTransport"
-	Api_ExtendLicense_FullMethodName = "/Api/ExtendLicense"
-	Api_StateStream_FullMethodName   = "/Api/StateStream"
+	Api_Register_FullMethodName         = "/Api/Register"
+	Api_Login_FullMethodName            = "/Api/Login"
+	Api_GetSession_FullMethodName       = "/Api/GetSession"
+	Api_GetSetup_FullMethodName         = "/Api/GetSetup"
+	Api_NewTransport_FullMethodName     = "/Api/NewTransport"
+	Api_RemoveTransport_FullMethodName  = "/Api/RemoveTransport"
+	Api_UpgradeTransport_FullMethodName = "/Api/UpgradeTransport"
+	Api_ExtendLicense_FullMethodName    = "/Api/ExtendLicense"
+	Api_StateStream_FullMethodName      = "/Api/StateStream"
 )
 
 // ApiClient is the client API for Api service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ApiClient interface {
-	GetSession(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*Session, error)
-	GetSetup(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Setup, error)
+	Register(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*AuthToken, error)
+	Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*AuthToken, error)
+	GetSession(ctx context.Context, in *GetSessionReq, opts ...grpc.CallOption) (*Session, error)
+	GetSetup(ctx context.Context, in *SetupReq, opts ...grpc.CallOption) (*Setup, error)
 	NewTransport(ctx context.Context, in *NewTransportReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveTransport(ctx context.Context, in *RemoveTransportReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpgradeTransport(ctx context.Context, in *UpgradeTransportReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	ExtendLicense(ctx context.Context, in *ExtendLicenseReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// rpc EventStream(UserId) returns (stream Event);
 	StateStream(ctx context.Context, in *StateStreamReq, opts ...grpc.CallOption) (Api_StateStreamClient, error)
@@ -47,7 +55,25 @@ func NewApiClient(cc grpc.ClientConnInterface) ApiClient {
 	return &apiClient{cc}
 }
 
-func (c *apiClient) GetSession(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*Session, error) {
+func (c *apiClient) Register(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*AuthToken, error) {
+	out := new(AuthToken)
+	err := c.cc.Invoke(ctx, Api_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*AuthToken, error) {
+	out := new(AuthToken)
+	err := c.cc.Invoke(ctx, Api_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) GetSession(ctx context.Context, in *GetSessionReq, opts ...grpc.CallOption) (*Session, error) {
 	out := new(Session)
 	err := c.cc.Invoke(ctx, Api_GetSession_FullMethodName, in, out, opts...)
 	if err != nil {
@@ -56,7 +82,7 @@ func (c *apiClient) GetSession(ctx context.Context, in *UserId, opts ...grpc.Cal
 	return out, nil
 }
 
-func (c *apiClient) GetSetup(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Setup, error) {
+func (c *apiClient) GetSetup(ctx context.Context, in *SetupReq, opts ...grpc.CallOption) (*Setup, error) {
 	out := new(Setup)
 	err := c.cc.Invoke(ctx, Api_GetSetup_FullMethodName, in, out, opts...)
 	if err != nil {
@@ -74,6 +100,24 @@ func (c *apiClient) NewTransport(ctx context.Context, in *NewTransportReq, opts
 	return out, nil
 }
 
+func (c *apiClient) RemoveTransport(ctx context.Context, in *RemoveTransportReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Api_RemoveTransport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) UpgradeTransport(ctx context.Context, in *UpgradeTransportReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Api_UpgradeTransport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *apiClient) ExtendLicense(ctx context.Context, in *ExtendLicenseReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, Api_ExtendLicense_FullMethodName, in, out, opts...)
@@ -119,9 +163,13 @@ func (x *apiStateStreamClient) Recv() (*State, error) {
 // All implementations must embed UnimplementedApiServer
 // for forward compatibility
 type ApiServer interface {
-	GetSession(context.Context, *UserId) (*Session, error)
-	GetSetup(context.Context, *emptypb.Empty) (*Setup, error)
+	Register(context.Context, *Credentials) (*AuthToken, error)
+	Login(context.Context, *Credentials) (*AuthToken, error)
+	GetSession(context.Context, *GetSessionReq) (*Session, error)
+	GetSetup(context.Context, *SetupReq) (*Setup, error)
 	NewTransport(context.Context, *NewTransportReq) (*emptypb.Empty, error)
+	RemoveTransport(context.Context, *RemoveTransportReq) (*emptypb.Empty, error)
+	UpgradeTransport(context.Context, *UpgradeTransportReq) (*emptypb.Empty, error)
 	ExtendLicense(context.Context, *ExtendLicenseReq) (*emptypb.Empty, error)
 	// rpc EventStream(UserId) returns (stream Event);
 	StateStream(*StateStreamReq, Api_StateStreamServer) error
@@ -132,15 +180,27 @@ type ApiServer interface {
 type UnimplementedApiServer struct {
 }
 
-func (UnimplementedApiServer) GetSession(context.Context, *UserId) (*Session, error) {
+func (UnimplementedApiServer) Register(context.Context, *Credentials) (*AuthToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedApiServer) Login(context.Context, *Credentials) (*AuthToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedApiServer) GetSession(context.Context, *GetSessionReq) (*Session, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
 }
-func (UnimplementedApiServer) GetSetup(context.Context, *emptypb.Empty) (*Setup, error) {
+func (UnimplementedApiServer) GetSetup(context.Context, *SetupReq) (*Setup, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSetup not implemented")
 }
 func (UnimplementedApiServer) NewTransport(context.Context, *NewTransportReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NewTransport not implemented")
 }
+func (UnimplementedApiServer) RemoveTransport(context.Context, *RemoveTransportReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTransport not implemented")
+}
+func (UnimplementedApiServer) UpgradeTransport(context.Context, *UpgradeTransportReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeTransport not implemented")
+}
 func (UnimplementedApiServer) ExtendLicense(context.Context, *ExtendLicenseReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExtendLicense not implemented")
 }
@@ -160,8 +220,44 @@ func RegisterApiServer(s grpc.ServiceRegistrar, srv ApiServer) {
 	s.RegisterService(&Api_ServiceDesc, srv)
 }
 
+func _Api_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Credentials)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Api_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Register(ctx, req.(*Credentials))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Credentials)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Api_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Login(ctx, req.(*Credentials))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_GetSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserId)
+	in := new(GetSessionReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -173,13 +269,13 @@ func _Api_GetSession_Handler(srv interface{}, ctx context.Context, dec func(inte
 		FullMethod: Api_GetSession_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ApiServer).GetSession(ctx, req.(*UserId))
+		return srv.(ApiServer).GetSession(ctx, req.(*GetSessionReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _Api_GetSetup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(SetupReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -191,7 +287,7 @@ func _Api_GetSetup_Handler(srv interface{}, ctx context.Context, dec func(interf
 		FullMethod: Api_GetSetup_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ApiServer).GetSetup(ctx, req.(*emptypb.Empty))
+		return srv.(ApiServer).GetSetup(ctx, req.(*SetupReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -214,6 +310,42 @@ func _Api_NewTransport_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_RemoveTransport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTransportReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).RemoveTransport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Api_RemoveTransport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).RemoveTransport(ctx, req.(*RemoveTransportReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_UpgradeTransport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeTransportReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).UpgradeTransport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Api_UpgradeTransport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).UpgradeTransport(ctx, req.(*UpgradeTransportReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_ExtendLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExtendLicenseReq)
 	if err := dec(in); err != nil {
@@ -260,6 +392,14 @@ var Api_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "Api",
 	HandlerType: (*ApiServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Api_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Api_Login_Handler,
+		},
 		{
 			MethodName: "GetSession",
 			Handler:    _Api_GetSession_Handler,
@@ -272,6 +412,14 @@ var Api_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NewTransport",
 			Handler:    _Api_NewTransport_Handler,
 		},
+		{
+			MethodName: "RemoveTransport",
+			Handler:    _Api_RemoveTransport_Handler,
+		},
+		{
+			MethodName: "UpgradeTransport",
+			Handler:    _Api_UpgradeTransport_Handler,
+		},
 		{
 			MethodName: "ExtendLicense",
 			Handler:    _Api_ExtendLicense_Handler,
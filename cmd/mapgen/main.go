@@ -0,0 +1,49 @@
+// mapgen writes a generated map as a map file to start a hand-authored map from.
+//
+//	go run ./cmd/mapgen -width 16 -height 12 -players 4 -seed 7 > config/maps/my_map.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"game_server/internal/game"
+	"log"
+	"os"
+)
+
+func main() {
+	rulesFile := flag.String("rules", "", "json file with game rules, defaults are used if empty")
+	generator := flag.String("generator", "", "map generator, the rules one if empty")
+	width := flag.Int("width", 0, "map width, the rules one if 0")
+	height := flag.Int("height", 0, "map height, the rules one if 0")
+	players := flag.Int("players", 0, "number of start areas, maxPlayers of the rules if 0")
+	seed := flag.Int64("seed", 1, "seed of the map")
+	flag.Parse()
+
+	rules, err := game.LoadRules(*rulesFile)
+	if err != nil {
+		log.Fatalf("failed to load game rules: %v", err)
+	}
+
+	if *generator != "" {
+		rules.MapGenerator = *generator
+	}
+	if *width != 0 {
+		rules.Width = int32(*width)
+	}
+	if *height != 0 {
+		rules.Height = int32(*height)
+	}
+	if *players != 0 {
+		rules.MaxPlayers = *players
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatalf("invalid rules: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(game.GenerateMapFile(rules, *seed)); err != nil {
+		log.Fatalf("failed to write map: %v", err)
+	}
+}
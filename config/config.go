@@ -2,15 +2,28 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 )
 
 type Config struct {
 	Port   string `env:"PORT" envDefault:"8080"`
+	DbType string `env:"DB_TYPE" envDefault:"tarantool"` // tarantool or memory
 	DbHost string `env:"DB_HOST" envDefault:""`
 	DbUser string `env:"DB_USER" envDefault:""`
 	DbPass string `env:"DB_PASS" envDefault:""`
+
+	RulesFile string `env:"RULES_FILE" envDefault:""` // json file with game rules, see game.Rules
+	ModesFile string `env:"MODES_FILE" envDefault:""` // json file with game modes, see game.LoadModes
+	MapsDir   string `env:"MAPS_DIR" envDefault:""`   // dir with hand-authored map files, see game.MapFile
+
+	AuthSecret string        `env:"AUTH_SECRET" envDefault:""` // random one is generated if empty
+	TokenTTL   time.Duration `env:"TOKEN_TTL" envDefault:"24h"`
+
+	StreamBuffer     int    `env:"STREAM_BUFFER" envDefault:"16"`              // states queued per state stream
+	SlowStreamPolicy string `env:"SLOW_STREAM_POLICY" envDefault:"disconnect"` // drop or disconnect, see game.StreamOptions
+	StreamHistory    int    `env:"STREAM_HISTORY" envDefault:"60"`             // states kept to resume reconnected streams
 }
 
 func ReadConfig() (*Config, error) {
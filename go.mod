@@ -6,7 +6,10 @@ require (
 	github.com/caarlos0/env/v6 v6.10.1
 	github.com/json-iterator/go v1.1.12
 	github.com/spf13/cast v1.6.0
+	github.com/tarantool/go-iproto v1.0.0
 	github.com/tarantool/go-tarantool/v2 v2.1.0
+	golang.org/x/crypto v0.20.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240227224415-6ceb2ff114de
 	google.golang.org/grpc v1.63.2
 	google.golang.org/protobuf v1.34.1
 )
@@ -14,11 +17,9 @@ require (
 require (
 	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	github.com/tarantool/go-iproto v1.0.0 // indirect
 	github.com/vmihailenco/msgpack/v5 v5.3.5 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240227224415-6ceb2ff114de // indirect
 )
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userIdKey struct{}
+
+// UserIdFromContext returns id of the user authenticated by the interceptors
+func UserIdFromContext(ctx context.Context) (int32, bool) {
+	userId, ok := ctx.Value(userIdKey{}).(int32)
+	return userId, ok
+}
+
+func contextWithUserId(ctx context.Context, userId int32) context.Context {
+	return context.WithValue(ctx, userIdKey{}, userId)
+}
+
+// Interceptors authenticate every rpc except the public ones by the
+// "authorization: Bearer <token>" metadata
+type Interceptors struct {
+	tokens        *TokenManager
+	publicMethods map[string]bool
+}
+
+// NewInterceptors creates interceptors, publicMethods are full method names
+// (e.g. "/Api/Login") which don't require a token
+func NewInterceptors(tokens *TokenManager, publicMethods ...string) *Interceptors {
+	public := map[string]bool{}
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return &Interceptors{
+		tokens:        tokens,
+		publicMethods: public,
+	}
+}
+
+func (i *Interceptors) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if i.publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (i *Interceptors) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.publicMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := i.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (i *Interceptors) authenticate(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	userId, err := i.tokens.Verify(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+
+	return contextWithUserId(ctx, userId), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+var encoding = base64.RawURLEncoding
+
+type claims struct {
+	UserId    int32 `json:"uid"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// TokenManager issues and verifies signed tokens of the form
+// base64(claims).base64(hmac-sha256(claims))
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokenManager creates manager signing tokens with the secret. With empty
+// secret a random one is generated, so tokens don't survive server restart.
+func NewTokenManager(secret string, ttl time.Duration) (*TokenManager, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generate token secret error: %w", err)
+		}
+	}
+
+	return &TokenManager{
+		secret: key,
+		ttl:    ttl,
+	}, nil
+}
+
+func (tm *TokenManager) Issue(userId int32) (string, time.Time, error) {
+	expiresAt := time.Now().Add(tm.ttl)
+
+	payload, err := json.Marshal(claims{UserId: userId, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token := encoding.EncodeToString(payload) + "." + encoding.EncodeToString(tm.sign(payload))
+	return token, expiresAt, nil
+}
+
+// Verify checks the token signature and expiration and returns the user id
+func (tm *TokenManager) Verify(token string) (int32, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidToken
+	}
+
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	if !hmac.Equal(signature, tm.sign(payload)) {
+		return 0, ErrInvalidToken
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	if time.Now().After(time.Unix(c.ExpiresAt, 0)) {
+		return 0, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+
+	return c.UserId, nil
+}
+
+func (tm *TokenManager) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, tm.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
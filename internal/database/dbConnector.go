@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	pb "game_server/api/v1"
+	"math"
+	"math/rand"
 	"time"
 
 	"github.com/spf13/cast"
 
 	jsoniter "github.com/json-iterator/go"
 
+	"github.com/tarantool/go-iproto"
 	"github.com/tarantool/go-tarantool/v2"
 )
 
@@ -53,18 +56,55 @@ func sessionToTntTuple(session *pb.Session) []interface{} {
 		session.TimeLimit,
 		session.Status,
 		session.StartTime,
+		session.Mode,
+		session.Width,
+		session.Height,
+		session.Seed,
+		session.MapName,
 	}
 }
 
+// joinedusers space format: {user_id, session_id, status}
+// with primary index on {user_id, session_id} and non-unique index "user" on user_id
+func joinedUserToTntTuple(userId int32, session *pb.Session) []interface{} {
+	return []interface{}{
+		uint64(userId),
+		uint64(session.Id),
+		session.Status,
+	}
+}
+
+func isAliveStatus(status pb.SessionStatus) bool {
+	return status == pb.SessionStatus_WAITING || status == pb.SessionStatus_ACTIVE
+}
+
 func tntTupleToSession(tuple []interface{}) (*pb.Session, error) {
-	b, err := json.Marshal(map[string]interface{}{
+	fields := map[string]interface{}{
 		"id":        tuple[0],
 		"users":     tuple[1],
 		"map":       tuple[2],
 		"timeLimit": tuple[3],
 		"status":    tuple[4],
 		"startTime": tuple[5],
-	})
+	}
+	// sessions stored before game modes were introduced don't have the mode field
+	if len(tuple) > 6 {
+		fields["mode"] = tuple[6]
+	}
+	// and sessions stored before non-square maps don't have the map size
+	if len(tuple) > 8 {
+		fields["width"] = tuple[7]
+		fields["height"] = tuple[8]
+	}
+	// and sessions stored before seeds and hand-authored maps have neither
+	if len(tuple) > 9 {
+		fields["seed"] = tuple[9]
+	}
+	if len(tuple) > 10 {
+		fields["mapName"] = tuple[10]
+	}
+
+	b, err := json.Marshal(fields)
 
 	if err != nil {
 		return nil, err
@@ -94,7 +134,7 @@ func (db *DbConnector) UpdateSession(session *pb.Session) error {
 		return fmt.Errorf("update session db error: %v", err)
 	}
 
-	return nil
+	return db.updateJoinedUsers(session)
 }
 
 func (db *DbConnector) GetSession(id int32) (*pb.Session, error) {
@@ -120,9 +160,10 @@ func (db *DbConnector) GetSession(id int32) (*pb.Session, error) {
 	return tntTupleToSession(data[0].([]interface{}))
 }
 
+// GetAliveSessionByUser returns the WAITING or ACTIVE session the user has joined
 func (db *DbConnector) GetAliveSessionByUser(userId int32) (*pb.Session, error) {
 	// First step is to get the session id for the user
-	req := tarantool.NewSelectRequest("joinedusers")
+	req := tarantool.NewSelectRequest("joinedusers").Index("user").Iterator(tarantool.IterEq).Key([]interface{}{uint64(userId)})
 	resp, err := db.conn.Do(req).GetResponse()
 	if err != nil {
 		return nil, fmt.Errorf("can't find session for user id %d: %w", userId, err)
@@ -139,7 +180,7 @@ func (db *DbConnector) GetAliveSessionByUser(userId int32) (*pb.Session, error)
 
 	for _, tuple := range data {
 		tuple2 := tuple.([]interface{})
-		if cast.ToInt32(tuple2[0]) == userId {
+		if isAliveStatus(pb.SessionStatus(cast.ToInt32(tuple2[2]))) {
 			sessionId := cast.ToInt32(tuple2[1])
 			return db.GetSession(sessionId)
 		}
@@ -147,3 +188,85 @@ func (db *DbConnector) GetAliveSessionByUser(userId int32) (*pb.Session, error)
 
 	return nil, ErrSessionNotFound
 }
+
+// updateJoinedUsers writes the user->session membership of every session user.
+// Entries of a FINISHED session are kept as an archive and are skipped on lookup.
+func (db *DbConnector) updateJoinedUsers(session *pb.Session) error {
+	futures := make([]*tarantool.Future, 0, len(session.Users))
+	for _, user := range session.Users {
+		req := tarantool.NewReplaceRequest("joinedusers").Tuple(joinedUserToTntTuple(user.Id, session))
+		futures = append(futures, db.conn.Do(req))
+	}
+
+	for _, future := range futures {
+		if _, err := future.Get(); err != nil {
+			return fmt.Errorf("update joined users of session %d db error: %v", session.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// addUserAttempts limits the retries of AddUser when the random id is taken
+const addUserAttempts = 5
+
+// users space format: {id, name, password_hash}
+// with primary index on id and unique index "name" on name
+func (db *DbConnector) AddUser(name string, passwordHash []byte) (int32, error) {
+	if _, err := db.GetUserByName(name); err == nil {
+		return 0, ErrUserExists
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return 0, err
+	}
+
+	for attempt := 0; attempt < addUserAttempts; attempt++ {
+		id := 1 + rand.Int31n(math.MaxInt32-1) // 0 is reserved
+		req := tarantool.NewInsertRequest("users").Tuple([]interface{}{uint64(id), name, passwordHash})
+		_, err := db.conn.Do(req).Get()
+		if err == nil {
+			return id, nil
+		}
+
+		var tntErr tarantool.Error
+		if !errors.As(err, &tntErr) || tntErr.Code != iproto.ER_TUPLE_FOUND {
+			return 0, fmt.Errorf("add user db error: %v", err)
+		}
+
+		// either the name is taken by a concurrent registration or the id by another user
+		if _, err := db.GetUserByName(name); err == nil {
+			return 0, ErrUserExists
+		} else if !errors.Is(err, ErrUserNotFound) {
+			return 0, err
+		}
+	}
+
+	return 0, fmt.Errorf("add user db error: no free id after %d attempts", addUserAttempts)
+}
+
+func (db *DbConnector) GetUserByName(name string) (*User, error) {
+	req := tarantool.NewSelectRequest("users").Index("name").Iterator(tarantool.IterEq).Key([]interface{}{name})
+	resp, err := db.conn.Do(req).GetResponse()
+	if err != nil {
+		return nil, fmt.Errorf("can't get user %s: %w", name, err)
+	}
+	selResp, ok := resp.(*tarantool.SelectResponse)
+	if !ok {
+		return nil, errors.New("wrong response type")
+	}
+
+	data, err := selResp.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	tuple := data[0].([]interface{})
+	return &User{
+		Id:           cast.ToInt32(tuple[0]),
+		Name:         cast.ToString(tuple[1]),
+		PasswordHash: []byte(cast.ToString(tuple[2])),
+	}, nil
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	pb "game_server/api/v1"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryStore keeps sessions in process memory. Sessions are copied on the
+// way in and out, so callers get the same semantics as with a real database.
+type MemoryStore struct {
+	mutex       sync.RWMutex
+	sessions    map[int32]*pb.Session
+	joinedUsers map[int32]map[int32]pb.SessionStatus //key: userId, sessionId
+	users       map[string]*User                     //key: name
+	lastUserId  int32
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:    map[int32]*pb.Session{},
+		joinedUsers: map[int32]map[int32]pb.SessionStatus{},
+		users:       map[string]*User{},
+	}
+}
+
+func (ms *MemoryStore) Close() error {
+	return nil
+}
+
+func (ms *MemoryStore) AddSession(session *pb.Session) error {
+	return ms.UpdateSession(session)
+}
+
+func (ms *MemoryStore) UpdateSession(session *pb.Session) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.sessions[session.Id] = proto.Clone(session).(*pb.Session)
+	for _, user := range session.Users {
+		joined, ok := ms.joinedUsers[user.Id]
+		if !ok {
+			joined = map[int32]pb.SessionStatus{}
+			ms.joinedUsers[user.Id] = joined
+		}
+		joined[session.Id] = session.Status
+	}
+
+	return nil
+}
+
+func (ms *MemoryStore) GetSession(id int32) (*pb.Session, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	session, ok := ms.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	return proto.Clone(session).(*pb.Session), nil
+}
+
+// GetAliveSessionByUser returns the WAITING or ACTIVE session the user has
+// joined. Of several ones the session with the least id is returned, like the
+// joinedusers index of DbConnector orders them.
+func (ms *MemoryStore) GetAliveSessionByUser(userId int32) (*pb.Session, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	found := false
+	var aliveId int32
+	for sessionId, status := range ms.joinedUsers[userId] {
+		if isAliveStatus(status) && (!found || sessionId < aliveId) {
+			aliveId = sessionId
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, ErrSessionNotFound
+	}
+
+	return proto.Clone(ms.sessions[aliveId]).(*pb.Session), nil
+}
+
+func (ms *MemoryStore) AddUser(name string, passwordHash []byte) (int32, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, ok := ms.users[name]; ok {
+		return 0, ErrUserExists
+	}
+
+	ms.lastUserId++
+	ms.users[name] = &User{
+		Id:           ms.lastUserId,
+		Name:         name,
+		PasswordHash: passwordHash,
+	}
+
+	return ms.lastUserId, nil
+}
+
+func (ms *MemoryStore) GetUserByName(name string) (*User, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	user, ok := ms.users[name]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	userCopy := *user
+	return &userCopy, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+	pb "game_server/api/v1"
+)
+
+// SessionStore is the storage used by the server and the game loop to keep sessions.
+// It owns the user->session membership: AddSession and UpdateSession record every
+// session user, and GetAliveSessionByUser only returns WAITING or ACTIVE sessions.
+type SessionStore interface {
+	AddSession(session *pb.Session) error
+	UpdateSession(session *pb.Session) error
+	GetSession(id int32) (*pb.Session, error)
+	GetAliveSessionByUser(userId int32) (*pb.Session, error)
+	Close() error
+}
+
+// Store is the storage of sessions and players
+type Store interface {
+	SessionStore
+	UserStore
+}
+
+var (
+	_ Store = (*DbConnector)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+const (
+	StoreTarantool = "tarantool"
+	StoreMemory    = "memory"
+)
+
+// NewStore creates the store of the given type
+func NewStore(storeType, host, user, pass string) (Store, error) {
+	switch storeType {
+	case StoreTarantool:
+		return NewDbConnector(host, user, pass)
+	case StoreMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown session store type %q", storeType)
+	}
+}
@@ -0,0 +1,23 @@
+package database
+
+import "errors"
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+)
+
+// User is a registered player account
+type User struct {
+	Id           int32
+	Name         string
+	PasswordHash []byte
+}
+
+// UserStore keeps registered players
+type UserStore interface {
+	// AddUser stores a new user with unique name and returns its id. Id 0 is
+	// never given, the routes of nobody belong to it.
+	AddUser(name string, passwordHash []byte) (int32, error)
+	GetUserByName(name string) (*User, error)
+}
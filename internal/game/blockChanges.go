@@ -0,0 +1,43 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// BlockChanges collects the indexes of the map blocks changed since the last
+// state, so the changed blocks are found without comparing the whole map
+type BlockChanges struct {
+	marked  map[int]bool
+	indexes []int // in the order of marking
+}
+
+func NewBlockChanges() *BlockChanges {
+	return &BlockChanges{
+		marked:  map[int]bool{},
+		indexes: []int{},
+	}
+}
+
+// Mark records the change of the block with the index in the session map
+func (c *BlockChanges) Mark(index int) {
+	if c.marked[index] {
+		return
+	}
+
+	c.marked[index] = true
+	c.indexes = append(c.indexes, index)
+}
+
+// Take returns copies of the changed blocks of the map and forgets the changes
+func (c *BlockChanges) Take(blocks []*pb.Block) []*pb.Block {
+	changed := make([]*pb.Block, 0, len(c.indexes))
+	for _, index := range c.indexes {
+		changed = append(changed, proto.Clone(blocks[index]).(*pb.Block))
+		delete(c.marked, index)
+	}
+	c.indexes = c.indexes[:0]
+
+	return changed
+}
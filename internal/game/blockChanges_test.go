@@ -0,0 +1,112 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"math/rand"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	benchMapSide     = 200
+	benchTickChanges = 4 // blocks changed every tick
+)
+
+func TestBlockChangesTake(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	blocks := benchMap(rng, 4)
+	changes := NewBlockChanges()
+
+	changes.Mark(5)
+	changes.Mark(2)
+	changes.Mark(5)
+
+	changed := changes.Take(blocks)
+	if len(changed) != 2 || !proto.Equal(changed[0], blocks[5]) || !proto.Equal(changed[1], blocks[2]) {
+		t.Fatalf("changed blocks %v, want blocks 5 and 2 once in the order of marking", changed)
+	}
+	if changed[0] == blocks[5] {
+		t.Errorf("changed block shares the message with the map")
+	}
+	if again := changes.Take(blocks); len(again) != 0 {
+		t.Errorf("changes are not forgotten after Take, got %v", again)
+	}
+}
+
+// BenchmarkBlockChangesTake finds the changed blocks of a tick by dirty tracking
+//
+//	go test -bench BlockChanges -benchmem ./internal/game/
+func BenchmarkBlockChangesTake(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	blocks := benchMap(rng, benchMapSide)
+	changes := NewBlockChanges()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		changeBlocks(rng, blocks, benchTickChanges, changes)
+		_ = changes.Take(blocks)
+	}
+}
+
+// BenchmarkBlockChangesFullDiff finds the changed blocks of a tick by comparing
+// every block with the previous state, the way dirty tracking replaced
+func BenchmarkBlockChangesFullDiff(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	blocks := benchMap(rng, benchMapSide)
+	last := cloneBlocks(blocks)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		changeBlocks(rng, blocks, benchTickChanges, nil)
+
+		changed := []*pb.Block{}
+		for j := range blocks {
+			if !proto.Equal(blocks[j], last[j]) {
+				changed = append(changed, proto.Clone(blocks[j]).(*pb.Block))
+			}
+		}
+		last = cloneBlocks(blocks)
+	}
+}
+
+func benchMap(rng *rand.Rand, side int32) []*pb.Block {
+	blocks := make([]*pb.Block, 0, side*side)
+	for y := int32(0); y < side; y++ {
+		for x := int32(0); x < side; x++ {
+			blocks = append(blocks, &pb.Block{
+				Position:   &pb.Coordintates{X: x, Y: y},
+				Type:       pb.BlockType(rng.Intn(len(pb.BlockType_name))),
+				Capacity:   int32(1 + rng.Intn(4)),
+				Connectors: []*pb.Connector{},
+			})
+		}
+	}
+	return blocks
+}
+
+func cloneBlocks(blocks []*pb.Block) []*pb.Block {
+	clone := make([]*pb.Block, 0, len(blocks))
+	for _, block := range blocks {
+		clone = append(clone, proto.Clone(block).(*pb.Block))
+	}
+	return clone
+}
+
+// changeBlocks adds a connector to n random blocks like building a route does
+func changeBlocks(rng *rand.Rand, blocks []*pb.Block, n int, changes *BlockChanges) {
+	for i := 0; i < n; i++ {
+		index := rng.Intn(len(blocks))
+		block := blocks[index]
+		if len(block.Connectors) > 8 {
+			block.Connectors = block.Connectors[:0]
+		}
+		block.Connectors = append(block.Connectors, &pb.Connector{Transport: pb.Transport_BUS, Destination: block.Position})
+
+		if changes != nil {
+			changes.Mark(index)
+		}
+	}
+}
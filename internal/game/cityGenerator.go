@@ -0,0 +1,308 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	cityAttempts      = 8    // maps generated to find a fair one
+	cityFairSpread    = 0.15 // surroundings of start areas differ at most by this share
+	cityNoiseCellSize = 4    // blocks per cell of the district borders noise
+	citySurroundings  = 3    // blocks around a start area compared for fairness
+	maxBlockCapacity  = 5
+)
+
+// cityGenerator builds districts around the map centre: the entertainment
+// centre, dense residential blocks around it, the industrial belt and the
+// residential suburbs, with small technical zones spread over the city.
+// Capacity grows with the density, which falls from the centre to the edges.
+//
+// The start areas lie on the map border, so their surroundings are similar
+// by the layout; of several generated maps the one where they differ the
+// least is taken. If they still differ by more than cityFairSpread, the
+// surroundings of the taken map are balanced.
+type cityGenerator struct{}
+
+func (g cityGenerator) Generate(rng *rand.Rand, rules *Rules) []*pb.Block {
+	var best []*pb.Block
+	bestSpread := math.Inf(1)
+
+	for i := 0; i < cityAttempts; i++ {
+		blocks := g.generate(rng, rules.Width, rules.Height)
+
+		spread := startSurroundingsSpread(blocks, rules)
+		if spread < bestSpread {
+			best, bestSpread = blocks, spread
+		}
+		if spread <= cityFairSpread {
+			return best
+		}
+	}
+
+	balanceStartSurroundings(rng, best, rules)
+	return best
+}
+
+func (cityGenerator) generate(rng *rand.Rand, width, height int32) []*pb.Block {
+	noise := newValueNoise(rng, width, height, cityNoiseCellSize)
+	technical := technicalZones(rng, width, height)
+
+	centerX, centerY := float64(width-1)/2, float64(height-1)/2
+	halfWidth, halfHeight := math.Max(centerX, 1), math.Max(centerY, 1)
+
+	blocks := make([]*pb.Block, 0, width*height)
+	for y := int32(0); y < height; y++ {
+		for x := int32(0); x < width; x++ {
+			// 0 in the centre, 1 on the whole border, so the start areas
+			// anywhere on the border are equally far from the centre
+			dx, dy := (float64(x)-centerX)/halfWidth, (float64(y)-centerY)/halfHeight
+			distance := math.Max(math.Abs(dx), math.Abs(dy)) + 0.15*noise.at(x, y)
+
+			// suburbs are sparse, but still fit a few routes
+			blockType := pb.BlockType_RESIDENTIAL
+			density := clampFloat(1-distance, 0.3, 1)
+			switch {
+			case technical[Coords{X: x, Y: y}]:
+				blockType = pb.BlockType_TECHNICAL
+				density = 0.1
+			case distance < 0.25:
+				blockType = pb.BlockType_ENTERTAINMENT
+				density = 0.9
+			case distance > 0.55 && distance < 0.75:
+				blockType = pb.BlockType_INDUSTRIAL
+				density = 0.6
+			}
+
+			capacity := 1 + int32(math.Round(clampFloat(density+0.2*(rng.Float64()-0.5), 0, 1)*(maxBlockCapacity-1)))
+
+			blocks = append(blocks, &pb.Block{
+				Position:   &pb.Coordintates{X: x, Y: y},
+				Type:       blockType,
+				Capacity:   capacity,
+				Connectors: []*pb.Connector{},
+			})
+		}
+	}
+
+	return blocks
+}
+
+// technicalZones returns small blots of blocks, one per 100 blocks of the map
+func technicalZones(rng *rand.Rand, width, height int32) map[Coords]bool {
+	zones := map[Coords]bool{}
+
+	for i := int32(0); i < max(1, width*height/100); i++ {
+		center := Coords{X: rng.Int31n(width), Y: rng.Int31n(height)}
+		zones[center] = true
+		for _, neighbour := range center.neighbours() {
+			if rng.Intn(2) == 0 {
+				zones[neighbour] = true
+			}
+		}
+	}
+
+	return zones
+}
+
+// startSurroundings returns the blocks around every start area
+func startSurroundings(blocks []*pb.Block, rules *Rules) [][]*pb.Block {
+	gameMap := Map{Width: rules.Width, Height: rules.Height, blocks: blocks}
+	side := rules.LicenseAreaSideLen
+
+	surroundings := make([][]*pb.Block, 0, rules.MaxPlayers)
+	for i := 0; i < rules.MaxPlayers; i++ {
+		xStart, yStart := startArea(rules.Width, rules.Height, side, i, rules.MaxPlayers)
+
+		area := []*pb.Block{}
+		for y := yStart - citySurroundings; y < yStart+side+citySurroundings; y++ {
+			for x := xStart - citySurroundings; x < xStart+side+citySurroundings; x++ {
+				if block, ok := gameMap.Block(x, y); ok {
+					area = append(area, block)
+				}
+			}
+		}
+		surroundings = append(surroundings, area)
+	}
+
+	return surroundings
+}
+
+// surroundingsValue returns the mean capacity of the blocks as value[0] and
+// the share of the blocks of every type as value[1+type]
+func surroundingsValue(area []*pb.Block) []float64 {
+	value := make([]float64, 1+len(pb.BlockType_name))
+	for _, block := range area {
+		value[0] += float64(block.Capacity)
+		value[1+int(block.Type)]++
+	}
+	for m := range value {
+		value[m] /= float64(len(area))
+	}
+
+	return value
+}
+
+// startSurroundingsSpread compares the blocks around the start areas: the
+// mean capacity and the share of blocks of every type, so areas in the map
+// corners are comparable with the others. It returns the largest difference
+// between the areas, capacity as a share of the mean one.
+func startSurroundingsSpread(blocks []*pb.Block, rules *Rules) float64 {
+	values := make([][]float64, 0, rules.MaxPlayers)
+	for _, area := range startSurroundings(blocks, rules) {
+		values = append(values, surroundingsValue(area))
+	}
+
+	spread := 0.0
+	for m := range values[0] {
+		lo, hi, sum := math.Inf(1), math.Inf(-1), 0.0
+		for _, value := range values {
+			lo, hi, sum = math.Min(lo, value[m]), math.Max(hi, value[m]), sum+value[m]
+		}
+		if m == 0 {
+			spread = math.Max(spread, (hi-lo)/math.Max(sum/float64(len(values)), 1))
+		} else {
+			spread = math.Max(spread, hi-lo)
+		}
+	}
+
+	return spread
+}
+
+// balanceStartSurroundings brings the surroundings of every start area to the
+// mean of all of them: the share of every block type and the mean capacity.
+// Randomly picked blocks change their type and capacity, the blocks around
+// several start areas are kept.
+func balanceStartSurroundings(rng *rand.Rand, blocks []*pb.Block, rules *Rules) {
+	surroundings := startSurroundings(blocks, rules)
+
+	areasOf := map[*pb.Block]int{}
+	mean := make([]float64, 1+len(pb.BlockType_name))
+	for _, area := range surroundings {
+		for _, block := range area {
+			areasOf[block]++
+		}
+		for m, v := range surroundingsValue(area) {
+			mean[m] += v / float64(len(surroundings))
+		}
+	}
+
+	for _, area := range surroundings {
+		own := []*pb.Block{}
+		for _, i := range rng.Perm(len(area)) {
+			if areasOf[area[i]] == 1 {
+				own = append(own, area[i])
+			}
+		}
+
+		// types: the blocks of the types over the target take the types under it
+		count := make([]int, len(pb.BlockType_name))
+		for _, block := range area {
+			count[block.Type]++
+		}
+		target := roundShares(mean[1:], len(area))
+		for _, block := range own {
+			if count[block.Type] <= target[block.Type] {
+				continue
+			}
+			for t := range target {
+				if count[t] < target[t] {
+					count[block.Type]--
+					count[t]++
+					block.Type = pb.BlockType(t)
+					break
+				}
+			}
+		}
+
+		// capacity: one unit at a time, spread over the blocks
+		total := int32(0)
+		for _, block := range area {
+			total += block.Capacity
+		}
+		targetTotal := int32(math.Round(mean[0] * float64(len(area))))
+		for changed := true; total != targetTotal && changed; {
+			changed = false
+			for _, block := range own {
+				switch {
+				case total < targetTotal && block.Capacity < maxBlockCapacity:
+					block.Capacity++
+					total++
+					changed = true
+				case total > targetTotal && block.Capacity > 1:
+					block.Capacity--
+					total--
+					changed = true
+				}
+				if total == targetTotal {
+					break
+				}
+			}
+		}
+	}
+}
+
+// roundShares splits n into whole parts proportional to the shares, which
+// sum to 1, by the largest remainders
+func roundShares(shares []float64, n int) []int {
+	parts := make([]int, len(shares))
+	remainders := make([]int, len(shares))
+	left := n
+	for i, share := range shares {
+		parts[i] = int(share * float64(n))
+		left -= parts[i]
+		remainders[i] = i
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool {
+		ra := shares[remainders[a]]*float64(n) - float64(parts[remainders[a]])
+		rb := shares[remainders[b]]*float64(n) - float64(parts[remainders[b]])
+		return ra > rb
+	})
+	for i := 0; i < left && i < len(remainders); i++ {
+		parts[remainders[i]]++
+	}
+
+	return parts
+}
+
+// valueNoise is a smooth random field in [-1, 1], used to make district borders ragged
+type valueNoise struct {
+	cellSize int32
+	columns  int32
+	values   []float64
+}
+
+func newValueNoise(rng *rand.Rand, width, height, cellSize int32) valueNoise {
+	columns := width/cellSize + 2
+	rows := height/cellSize + 2
+
+	values := make([]float64, columns*rows)
+	for i := range values {
+		values[i] = 2*rng.Float64() - 1
+	}
+
+	return valueNoise{cellSize: cellSize, columns: columns, values: values}
+}
+
+// at interpolates the values of the cell corners around the block
+func (n valueNoise) at(x, y int32) float64 {
+	cellX, cellY := x/n.cellSize, y/n.cellSize
+	fx := float64(x%n.cellSize) / float64(n.cellSize)
+	fy := float64(y%n.cellSize) / float64(n.cellSize)
+
+	value := func(cx, cy int32) float64 {
+		return n.values[cy*n.columns+cx]
+	}
+
+	top := value(cellX, cellY)*(1-fx) + value(cellX+1, cellY)*fx
+	bottom := value(cellX, cellY+1)*(1-fx) + value(cellX+1, cellY+1)*fx
+
+	return top*(1-fy) + bottom*fy
+}
+
+func clampFloat(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCityGeneratorFairStarts(t *testing.T) {
+	const seeds = 200
+
+	tests := []struct {
+		players       int
+		width, height int32
+	}{
+		{players: 2, width: 12, height: 12},
+		{players: 4, width: 12, height: 12},
+		{players: 4, width: 24, height: 16},
+		{players: 3, width: 16, height: 24},
+		{players: 4, width: 40, height: 40},
+		{players: 6, width: 40, height: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d players %dx%d", tt.players, tt.width, tt.height), func(t *testing.T) {
+			rules := DefaultRules()
+			rules.MaxPlayers = tt.players
+			rules.Width, rules.Height = tt.width, tt.height
+
+			for seed := int64(1); seed <= seeds; seed++ {
+				blocks := cityGenerator{}.Generate(sessionRand(seed, randMap), rules)
+				if len(blocks) != int(tt.width*tt.height) {
+					t.Fatalf("seed %d: %d blocks, want %d", seed, len(blocks), tt.width*tt.height)
+				}
+				for _, block := range blocks {
+					if block.Capacity < 1 || block.Capacity > maxBlockCapacity {
+						t.Fatalf("seed %d: block (%d, %d) capacity %d", seed, block.Position.X, block.Position.Y, block.Capacity)
+					}
+				}
+				if spread := startSurroundingsSpread(blocks, rules); spread > cityFairSpread {
+					t.Errorf("seed %d: start surroundings spread %.3f, want at most %.2f", seed, spread, cityFairSpread)
+				}
+			}
+		})
+	}
+}
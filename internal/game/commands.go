@@ -0,0 +1,211 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// commandState is the session state a player command works on
+type commandState struct {
+	session    *pb.Session
+	gameMap    Map
+	rules      *Rules
+	network    *TransportNetwork
+	changes    *BlockChanges
+	travellers *travellers
+	rewards    *RewardQueue
+}
+
+// command is a player action. validate checks everything the action needs
+// without changing the state, so apply can't fail after successful validation.
+type command interface {
+	validate(state *commandState) error
+	apply(state *commandState)
+}
+
+type buildTransportCmd struct {
+	userId    int32
+	from      *pb.Coordintates
+	to        *pb.Coordintates
+	transport pb.Transport
+}
+
+func (c *buildTransportCmd) validate(state *commandState) error {
+	rules := state.rules
+	if err := validateNewTransport(state.gameMap, c.from, c.to, c.transport); err != nil {
+		return err
+	}
+
+	user := findUser(state.session, c.userId)
+	if err := validateBuildLicense(rules, user, c.from, c.to); err != nil {
+		return err
+	}
+
+	for _, position := range []*pb.Coordintates{c.from, c.to} {
+		block, _ := state.gameMap.Block(position.X, position.Y)
+		if len(block.Connectors) >= int(block.Capacity) {
+			return newError(KindFailedPrecondition, ReasonCapacityExceeded, "", "block (%d, %d) has no free connectors, capacity %d", position.X, position.Y, block.Capacity)
+		}
+	}
+
+	from, to := coordsOf(c.from), coordsOf(c.to)
+	if state.network.isPathExists(from, to) || state.network.isPathExists(to, from) {
+		return newError(KindAlreadyExists, ReasonRouteExists, "", "route between (%d, %d) and (%d, %d) already exists", from.X, from.Y, to.X, to.Y)
+	}
+
+	return rules.checkFunds(user, rules.transportCost(c.transport))
+}
+
+func (c *buildTransportCmd) apply(state *commandState) {
+	rules := state.rules
+
+	// validate has checked both blocks are on the map
+	fromIndex, _ := state.gameMap.Index(c.from.X, c.from.Y)
+	toIndex, _ := state.gameMap.Index(c.to.X, c.to.Y)
+	fromBlock := state.session.Map[fromIndex]
+	toBlock := state.session.Map[toIndex]
+	state.changes.Mark(fromIndex)
+	state.changes.Mark(toIndex)
+	fromBlock.Connectors = append(fromBlock.Connectors, &pb.Connector{UserId: c.userId, Transport: c.transport, Destination: c.to})
+	toBlock.Connectors = append(toBlock.Connectors, &pb.Connector{UserId: c.userId, Transport: c.transport, Destination: c.from})
+
+	from, to := coordsOf(c.from), coordsOf(c.to)
+	// validate has checked the route doesn't exist yet
+	_ = state.network.ConnectBlocks(c.userId, from, to, c.transport)
+
+	rules.charge(findUser(state.session, c.userId), rules.transportCost(c.transport))
+}
+
+type removeTransportCmd struct {
+	userId int32
+	from   *pb.Coordintates
+	to     *pb.Coordintates
+}
+
+func (c *removeTransportCmd) validate(state *commandState) error {
+	_, err := validateOwnRoute(state.gameMap, state.network, c.userId, c.from, c.to)
+	return err
+}
+
+func (c *removeTransportCmd) apply(state *commandState) {
+	from, to := coordsOf(c.from), coordsOf(c.to)
+	// validate has checked the route exists
+	route, _ := state.network.route(from, to)
+	state.network.DisconnectBlocks(from, to)
+
+	fromIndex, _ := state.gameMap.Index(c.from.X, c.from.Y)
+	toIndex, _ := state.gameMap.Index(c.to.X, c.to.Y)
+	removeConnector(state.session.Map[fromIndex], to)
+	removeConnector(state.session.Map[toIndex], from)
+	state.changes.Mark(fromIndex)
+	state.changes.Mark(toIndex)
+
+	state.travellers.cutRoute(time.Now(), from, to, state.rewards)
+
+	rules := state.rules
+	rules.refund(findUser(state.session, c.userId), rules.removeRefund(route.Transport))
+}
+
+type upgradeTransportCmd struct {
+	userId    int32
+	from      *pb.Coordintates
+	to        *pb.Coordintates
+	transport pb.Transport
+}
+
+func (c *upgradeTransportCmd) validate(state *commandState) error {
+	rules := state.rules
+	route, err := validateOwnRoute(state.gameMap, state.network, c.userId, c.from, c.to)
+	if err != nil {
+		return err
+	}
+	if err := validateTransport(c.transport); err != nil {
+		return err
+	}
+
+	if !route.inService(time.Now()) {
+		return newError(KindFailedPrecondition, ReasonRouteOutOfService, "", "route between (%d, %d) and (%d, %d) is being upgraded", c.from.X, c.from.Y, c.to.X, c.to.Y)
+	}
+	if rules.transportCost(c.transport) <= rules.transportCost(route.Transport) {
+		return newError(KindInvalidArgument, ReasonNotUpgrade, "transport", "%s is not an upgrade of %s", c.transport, route.Transport)
+	}
+
+	return rules.checkFunds(findUser(state.session, c.userId), rules.upgradeCost(route.Transport, c.transport))
+}
+
+func (c *upgradeTransportCmd) apply(state *commandState) {
+	rules := state.rules
+	now := time.Now()
+	from, to := coordsOf(c.from), coordsOf(c.to)
+
+	// validate has checked the route exists
+	route, _ := state.network.route(from, to)
+	cost := rules.upgradeCost(route.Transport, c.transport)
+
+	var inService time.Time
+	if rules.UpgradeDelay > 0 {
+		inService = now.Add(time.Duration(rules.UpgradeDelay))
+		// the route is closed under the passengers who haven't passed it
+		state.travellers.cutRoute(now, from, to, state.rewards)
+	}
+	_ = state.network.UpgradeRoute(from, to, c.transport, inService)
+
+	fromIndex, _ := state.gameMap.Index(c.from.X, c.from.Y)
+	toIndex, _ := state.gameMap.Index(c.to.X, c.to.Y)
+	upgradeConnector(state.session.Map[fromIndex], to, c.transport, inService)
+	upgradeConnector(state.session.Map[toIndex], from, c.transport, inService)
+	state.changes.Mark(fromIndex)
+	state.changes.Mark(toIndex)
+
+	rules.charge(findUser(state.session, c.userId), cost)
+}
+
+// upgradeConnector changes the transport of the block connector leading to the destination
+func upgradeConnector(block *pb.Block, destination Coords, transport pb.Transport, inService time.Time) {
+	for _, connector := range block.Connectors {
+		if coordsOf(connector.Destination) == destination {
+			connector.Transport = transport
+			connector.InServiceFrom = nil
+			if !inService.IsZero() {
+				connector.InServiceFrom = timestamppb.New(inService)
+			}
+			return
+		}
+	}
+}
+
+// removeConnector removes the connector of the block leading to the destination
+func removeConnector(block *pb.Block, destination Coords) {
+	for i, connector := range block.Connectors {
+		if coordsOf(connector.Destination) == destination {
+			block.Connectors = append(block.Connectors[:i], block.Connectors[i+1:]...)
+			return
+		}
+	}
+}
+
+type extendLicenseCmd struct {
+	userId int32
+	blocks []*pb.Coordintates
+}
+
+func (c *extendLicenseCmd) validate(state *commandState) error {
+	rules := state.rules
+	if err := validateExtendLicense(state.gameMap, state.session, c.userId, c.blocks); err != nil {
+		return err
+	}
+
+	return rules.checkFunds(findUser(state.session, c.userId), rules.LicenseCost*int32(len(c.blocks)))
+}
+
+func (c *extendLicenseCmd) apply(state *commandState) {
+	user := findUser(state.session, c.userId)
+	state.rules.charge(user, state.rules.LicenseCost*int32(len(c.blocks)))
+	user.License = append(user.License, c.blocks...)
+}
+
+func coordsOf(c *pb.Coordintates) Coords {
+	return Coords{X: c.X, Y: c.Y}
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"math/rand"
+	"sort"
+)
+
+// dayPhase is the part of the session day, travellers go to work in the
+// morning, to have fun in the daytime and back home in the evening
+type dayPhase int
+
+const (
+	morning dayPhase = iota
+	daytime
+	evening
+	dayPhases
+)
+
+// phaseOf returns the day phase of the game progress alpha, from 0 at the start to 1 at the end
+func phaseOf(alpha float64) dayPhase {
+	return dayPhase(clamp(int32(alpha*float64(dayPhases)), 0, int32(dayPhases-1)))
+}
+
+// demand[phase][from][to] is the relative number of trips from the blocks of
+// one type to the blocks of another one, types in order of pb.BlockType:
+// residential, entertainment, industrial, technical
+var demand = [dayPhases][4][4]float64{
+	morning: {
+		{1, 1, 6, 2},
+		{1, 0, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 1, 0},
+	},
+	daytime: {
+		{2, 3, 2, 1},
+		{2, 1, 0, 0},
+		{1, 2, 1, 0},
+		{0, 0, 1, 0},
+	},
+	evening: {
+		{1, 4, 0, 0},
+		{3, 1, 0, 0},
+		{6, 1, 0, 0},
+		{2, 0, 0, 0},
+	},
+}
+
+// trip is the journey of a traveller
+type trip struct {
+	from Coords
+	to   Coords
+}
+
+// demandModel picks the trips between the blocks served by the transport network
+type demandModel struct {
+	blocks  [4][]Coords // networked blocks by type
+	weights [4][]int32  // capacities of the blocks, busy blocks attract more travellers
+	totals  [4]int32
+}
+
+func newDemandModel(gameMap Map, network *TransportNetwork) *demandModel {
+	// map order is random, the session seed must decide alone
+	served := make([]Coords, 0, len(network.blocks))
+	for coords := range network.blocks {
+		served = append(served, coords)
+	}
+	sort.Slice(served, func(i, j int) bool {
+		return served[i].Y < served[j].Y || served[i].Y == served[j].Y && served[i].X < served[j].X
+	})
+
+	d := &demandModel{}
+	for _, coords := range served {
+		block, ok := gameMap.Block(coords.X, coords.Y)
+		if !ok || int(block.Type) >= len(d.blocks) {
+			continue
+		}
+
+		d.blocks[block.Type] = append(d.blocks[block.Type], coords)
+		d.weights[block.Type] = append(d.weights[block.Type], block.Capacity)
+		d.totals[block.Type] += block.Capacity
+	}
+
+	return d
+}
+
+// trip picks a trip of the phase, false if the network serves no demand
+func (d *demandModel) trip(rng *rand.Rand, phase dayPhase) (trip, bool) {
+	var pairs [][2]int
+	var weights []float64
+	for from := range demand[phase] {
+		for to, w := range demand[phase][from] {
+			if w > 0 && d.totals[from] > 0 && d.totals[to] > 0 {
+				pairs = append(pairs, [2]int{from, to})
+				weights = append(weights, w)
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return trip{}, false
+	}
+
+	pair := pairs[pickWeighted(rng, weights)]
+	return d.tripTo(rng, d.pick(rng, pair[0]), pair[1])
+}
+
+// tripFromBlock picks the destination of the traveller starting at the block
+func (d *demandModel) tripFromBlock(rng *rand.Rand, phase dayPhase, from Coords, fromType pb.BlockType) (trip, bool) {
+	weights := make([]float64, len(d.blocks))
+	for to, w := range demand[phase][fromType] {
+		if d.totals[to] > 0 {
+			weights[to] = w
+		}
+	}
+
+	to := pickWeighted(rng, weights)
+	if to < 0 {
+		return trip{}, false
+	}
+
+	return d.tripTo(rng, from, to)
+}
+
+// tripTo picks the destination of the type, false if it is the start block
+func (d *demandModel) tripTo(rng *rand.Rand, from Coords, toType int) (trip, bool) {
+	to := d.pick(rng, toType)
+	if to == from {
+		return trip{}, false
+	}
+
+	return trip{from: from, to: to}, true
+}
+
+// pick returns a block of the type with probability proportional to its capacity
+func (d *demandModel) pick(rng *rand.Rand, blockType int) Coords {
+	n := rng.Int31n(d.totals[blockType])
+	for i, w := range d.weights[blockType] {
+		if n < w {
+			return d.blocks[blockType][i]
+		}
+		n -= w
+	}
+
+	return d.blocks[blockType][len(d.blocks[blockType])-1]
+}
+
+// pickWeighted returns the index with probability proportional to its weight, -1 if all are zero
+func pickWeighted(rng *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	n := rng.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if n < w {
+			return i
+		}
+		n -= w
+		last = i
+	}
+
+	// rounding errors
+	return last
+}
@@ -0,0 +1,63 @@
+package game
+
+import "fmt"
+
+// ErrorKind classifies game errors, the server maps it to grpc status code
+type ErrorKind int
+
+const (
+	KindInvalidArgument ErrorKind = iota
+	KindNotFound
+	KindAlreadyExists
+	KindFailedPrecondition
+	KindResourceExhausted
+	KindPermissionDenied
+)
+
+// Reasons are machine readable error causes reported to clients
+const (
+	ReasonMissingField       = "MISSING_FIELD"
+	ReasonOutOfBounds        = "OUT_OF_BOUNDS"
+	ReasonUnknownTransport   = "UNKNOWN_TRANSPORT"
+	ReasonUnknownMode        = "UNKNOWN_MODE"
+	ReasonUnknownMap         = "UNKNOWN_MAP"
+	ReasonTooFewStartAreas   = "TOO_FEW_START_AREAS"
+	ReasonSameBlock          = "SAME_BLOCK"
+	ReasonDuplicateBlock     = "DUPLICATE_BLOCK"
+	ReasonAlreadyOwned       = "ALREADY_OWNED"
+	ReasonOwnedByOtherPlayer = "OWNED_BY_OTHER_PLAYER"
+	ReasonNotContiguous      = "LICENSE_NOT_CONTIGUOUS"
+	ReasonNotLicensed        = "ENDPOINT_NOT_LICENSED"
+	ReasonCapacityExceeded   = "CAPACITY_EXCEEDED"
+	ReasonRouteExists        = "ROUTE_EXISTS"
+	ReasonRouteNotFound      = "ROUTE_NOT_FOUND"
+	ReasonNotRouteOwner      = "NOT_ROUTE_OWNER"
+	ReasonRouteOutOfService  = "ROUTE_OUT_OF_SERVICE"
+	ReasonNotUpgrade         = "NOT_UPGRADE"
+	ReasonNotEnoughMoney     = "NOT_ENOUGH_MONEY"
+	ReasonSessionNotFound    = "SESSION_NOT_FOUND"
+	ReasonSessionNotActive   = "SESSION_NOT_ACTIVE"
+	ReasonNotPlayer          = "NOT_PLAYER"
+	ReasonSlowSubscriber     = "SLOW_SUBSCRIBER"
+)
+
+// Error is a violation of the game rules caused by the player request
+type Error struct {
+	Kind    ErrorKind
+	Reason  string
+	Field   string // request field the error is about, may be empty
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind ErrorKind, reason, field, format string, args ...any) *Error {
+	return &Error{
+		Kind:    kind,
+		Reason:  reason,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
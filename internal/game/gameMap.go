@@ -0,0 +1,84 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"math"
+)
+
+// Map is the grid of the session blocks stored row by row
+type Map struct {
+	Width  int32
+	Height int32
+	blocks []*pb.Block
+}
+
+// SessionMap returns the map of the session. Sessions stored before the map
+// size was recorded have square maps.
+func SessionMap(session *pb.Session) Map {
+	width, height := session.Width, session.Height
+	if width == 0 || height == 0 {
+		side := int32(math.Sqrt(float64(len(session.Map))))
+		width, height = side, side
+	}
+
+	return Map{
+		Width:  width,
+		Height: height,
+		blocks: session.Map,
+	}
+}
+
+func (m Map) Contains(x, y int32) bool {
+	return x >= 0 && x < m.Width && y >= 0 && y < m.Height
+}
+
+// Index returns the index of the block in the session map, false if it is out of the map
+func (m Map) Index(x, y int32) (int, bool) {
+	if !m.Contains(x, y) {
+		return 0, false
+	}
+	return int(y*m.Width + x), true
+}
+
+// Block returns the block at (x, y), false if it is out of the map
+func (m Map) Block(x, y int32) (*pb.Block, bool) {
+	index, ok := m.Index(x, y)
+	if !ok {
+		return nil, false
+	}
+	return m.blocks[index], true
+}
+
+// startArea returns the top left corner of the license area the player
+// starts with. The areas are spread evenly along the map border clockwise
+// starting from the top middle, so four players get:
+// ┌--------┐
+// |   0    |
+// |3      1|
+// |   2    |
+// └--------┘
+func startArea(width, height, areaSideLen int32, startPos, players int) (int32, int32) {
+	half := float64(areaSideLen) / 2
+	centerX, centerY := float64(width)/2, float64(height)/2
+
+	angle := 2 * math.Pi * float64(startPos) / float64(players)
+	dx, dy := math.Sin(angle), -math.Cos(angle)
+
+	// move from the map center towards the border until the area touches it
+	t := math.Inf(1)
+	if math.Abs(dx) > 1e-9 {
+		t = math.Min(t, (centerX-half)/math.Abs(dx))
+	}
+	if math.Abs(dy) > 1e-9 {
+		t = math.Min(t, (centerY-half)/math.Abs(dy))
+	}
+
+	x := int32(math.Round(centerX + t*dx - half))
+	y := int32(math.Round(centerY + t*dy - half))
+
+	return clamp(x, 0, width-areaSideLen), clamp(y, 0, height-areaSideLen)
+}
+
+func clamp(v, lo, hi int32) int32 {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,285 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	pb "game_server/api/v1"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Area is a rectangle of blocks
+type Area struct {
+	X      int32 `json:"x"`
+	Y      int32 `json:"y"`
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+}
+
+func (a Area) overlaps(b Area) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// nobody owns the routes of map files, no user gets its id
+const nobody int32 = 0
+
+// MapFile is a hand-authored map. Blocks are the rows of the map from the top,
+// every block is written as the letter of its type and its capacity, separated
+// by spaces, e.g. "R2 R3 E5 I4 T1". Routes are built before the game starts
+// and belong to nobody.
+//
+//	{
+//	    "width": 4,
+//	    "height": 2,
+//	    "blocks": ["R2 E5 E5 R2", "R2 I3 T1 R2"],
+//	    "startAreas": [{"x": 0, "y": 0, "width": 1, "height": 2}, {"x": 3, "y": 0, "width": 1, "height": 2}],
+//	    "routes": [{"from": {"x": 1, "y": 0}, "to": {"x": 2, "y": 0}, "transport": "METRO"}]
+//	}
+type MapFile struct {
+	Width      int32      `json:"width"`
+	Height     int32      `json:"height"`
+	Blocks     []string   `json:"blocks"`
+	StartAreas []Area     `json:"startAreas"` // license areas of the players in the order they join
+	Routes     []MapRoute `json:"routes,omitempty"`
+}
+
+type MapRoute struct {
+	From      Coords `json:"from"`
+	To        Coords `json:"to"`
+	Transport string `json:"transport"`
+}
+
+// Maps are the hand-authored maps sessions can be played on, key: map name
+type Maps map[string]*MapFile
+
+var blockTypeLetters = map[pb.BlockType]byte{
+	pb.BlockType_RESIDENTIAL:   'R',
+	pb.BlockType_ENTERTAINMENT: 'E',
+	pb.BlockType_INDUSTRIAL:    'I',
+	pb.BlockType_TECHNICAL:     'T',
+}
+
+// LoadMaps reads every *.json map file of the dir, the map name is the file
+// name without extension. Empty dir means no maps.
+func LoadMaps(dir string) (Maps, error) {
+	maps := Maps{}
+	if dir == "" {
+		return maps, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list maps dir %s error: %w", dir, err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read map file error: %w", err)
+		}
+
+		mapFile, err := ParseMapFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("map file %s: %w", path, err)
+		}
+
+		maps[strings.TrimSuffix(filepath.Base(path), ".json")] = mapFile
+	}
+
+	return maps, nil
+}
+
+// Get returns the map with the name
+func (m Maps) Get(name string) (*MapFile, error) {
+	mapFile, ok := m[name]
+	if !ok {
+		return nil, newError(KindInvalidArgument, ReasonUnknownMap, "map", "unknown map %q", name)
+	}
+
+	return mapFile, nil
+}
+
+func ParseMapFile(data []byte) (*MapFile, error) {
+	mapFile := &MapFile{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(mapFile); err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	if err := mapFile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid map: %w", err)
+	}
+
+	return mapFile, nil
+}
+
+func (f *MapFile) Validate() error {
+	if f.Width < 1 || f.Height < 1 || int64(f.Width)*int64(f.Height) > maxMapBlocks {
+		return fmt.Errorf("map size must be from 1x1 to %d blocks, got %dx%d", maxMapBlocks, f.Width, f.Height)
+	}
+
+	blocks, err := f.parseBlocks()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	gameMap := Map{Width: f.Width, Height: f.Height, blocks: blocks}
+
+	check(len(f.StartAreas) > 0, "at least one start area is required")
+	for i, area := range f.StartAreas {
+		check(area.Width >= 1 && area.Height >= 1, "startAreas[%d] must not be empty", i)
+		check(gameMap.Contains(area.X, area.Y) && gameMap.Contains(area.X+area.Width-1, area.Y+area.Height-1), "startAreas[%d] is out of the map", i)
+		for j := 0; j < i; j++ {
+			check(!area.overlaps(f.StartAreas[j]), "startAreas[%d] overlaps startAreas[%d]", i, j)
+		}
+	}
+
+	connectors := map[Coords]int32{}
+	routes := map[[2]Coords]bool{}
+	for i, route := range f.Routes {
+		_, ok := pb.Transport_value[route.Transport]
+		check(ok, "routes[%d] has unknown transport %q", i, route.Transport)
+		check(gameMap.Contains(route.From.X, route.From.Y) && gameMap.Contains(route.To.X, route.To.Y), "routes[%d] is out of the map", i)
+		check(route.From != route.To, "routes[%d] connects block with itself", i)
+		check(!routes[[2]Coords{route.From, route.To}] && !routes[[2]Coords{route.To, route.From}], "routes[%d] is duplicated", i)
+
+		routes[[2]Coords{route.From, route.To}] = true
+		connectors[route.From]++
+		connectors[route.To]++
+	}
+
+	for coords, n := range connectors {
+		if block, ok := gameMap.Block(coords.X, coords.Y); ok {
+			check(n <= block.Capacity, "block (%d, %d) has %d routes, capacity %d", coords.X, coords.Y, n, block.Capacity)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseBlocks reads the blocks without routes
+func (f *MapFile) parseBlocks() ([]*pb.Block, error) {
+	if len(f.Blocks) != int(f.Height) {
+		return nil, fmt.Errorf("map has %d rows of blocks, height %d", len(f.Blocks), f.Height)
+	}
+
+	letterTypes := map[byte]pb.BlockType{}
+	for blockType, letter := range blockTypeLetters {
+		letterTypes[letter] = blockType
+	}
+
+	blocks := make([]*pb.Block, 0, f.Width*f.Height)
+	for y, row := range f.Blocks {
+		tokens := strings.Fields(row)
+		if len(tokens) != int(f.Width) {
+			return nil, fmt.Errorf("row %d has %d blocks, width %d", y, len(tokens), f.Width)
+		}
+
+		for x, token := range tokens {
+			blockType, ok := letterTypes[token[0]]
+			capacity, err := strconv.ParseInt(token[1:], 10, 32)
+			if !ok || err != nil || capacity < 1 {
+				return nil, fmt.Errorf("block (%d, %d) %q must be a type letter (R, E, I, T) and positive capacity", x, y, token)
+			}
+
+			blocks = append(blocks, &pb.Block{
+				Position:   &pb.Coordintates{X: int32(x), Y: int32(y)},
+				Type:       blockType,
+				Capacity:   int32(capacity),
+				Connectors: []*pb.Connector{},
+			})
+		}
+	}
+
+	return blocks, nil
+}
+
+// NewBlocks returns the blocks of a new session on the map, the file must be valid
+func (f *MapFile) NewBlocks() []*pb.Block {
+	blocks, _ := f.parseBlocks()
+	gameMap := Map{Width: f.Width, Height: f.Height, blocks: blocks}
+
+	for _, route := range f.Routes {
+		transport := pb.Transport(pb.Transport_value[route.Transport])
+		from, _ := gameMap.Block(route.From.X, route.From.Y)
+		to, _ := gameMap.Block(route.To.X, route.To.Y)
+
+		from.Connectors = append(from.Connectors, &pb.Connector{UserId: nobody, Transport: transport, Destination: to.Position})
+		to.Connectors = append(to.Connectors, &pb.Connector{UserId: nobody, Transport: transport, Destination: from.Position})
+	}
+
+	return blocks
+}
+
+// ExportMap writes the session map as a map file with the start areas, the
+// routes of the session become routes of nobody
+func ExportMap(session *pb.Session, startAreas []Area) *MapFile {
+	gameMap := SessionMap(session)
+	mapFile := &MapFile{
+		Width:      gameMap.Width,
+		Height:     gameMap.Height,
+		Blocks:     make([]string, 0, gameMap.Height),
+		StartAreas: startAreas,
+		Routes:     []MapRoute{},
+	}
+
+	for y := int32(0); y < gameMap.Height; y++ {
+		tokens := make([]string, 0, gameMap.Width)
+		for x := int32(0); x < gameMap.Width; x++ {
+			block, _ := gameMap.Block(x, y)
+			tokens = append(tokens, fmt.Sprintf("%c%d", blockTypeLetters[block.Type], block.Capacity))
+
+			// every route is written once, from the block met first
+			for _, connector := range block.Connectors {
+				to := coordsOf(connector.Destination)
+				if to.Y > y || to.Y == y && to.X > x {
+					mapFile.Routes = append(mapFile.Routes, MapRoute{
+						From:      Coords{X: x, Y: y},
+						To:        to,
+						Transport: connector.Transport.String(),
+					})
+				}
+			}
+		}
+		mapFile.Blocks = append(mapFile.Blocks, strings.Join(tokens, " "))
+	}
+
+	return mapFile
+}
+
+// GenerateMapFile generates the map as a new session with the rules and seed
+// would get, to be edited by hand
+func GenerateMapFile(rules *Rules, seed int64) *MapFile {
+	session := &pb.Session{
+		Width:  rules.Width,
+		Height: rules.Height,
+		Map:    mapGenerators[rules.MapGenerator].Generate(sessionRand(seed, randMap), rules),
+	}
+
+	return ExportMap(session, generatedStartAreas(rules))
+}
+
+// generatedStartAreas returns the license areas of the players on generated maps
+func generatedStartAreas(rules *Rules) []Area {
+	side := rules.LicenseAreaSideLen
+	areas := make([]Area, 0, rules.MaxPlayers)
+	for i := 0; i < rules.MaxPlayers; i++ {
+		x, y := startArea(rules.Width, rules.Height, side, i, rules.MaxPlayers)
+		areas = append(areas, Area{X: x, Y: y, Width: side, Height: side})
+	}
+
+	return areas
+}
@@ -5,14 +5,33 @@ import (
 	"math/rand"
 )
 
-func generateMap() []*pb.Block {
+// Map generators
+const (
+	MapGeneratorUniform = "uniform" // every block type and capacity is random
+	MapGeneratorCity    = "city"    // districts around the city centre, see cityGenerator
+)
+
+// MapGenerator fills the blocks of a new session map row by row
+type MapGenerator interface {
+	Generate(rng *rand.Rand, rules *Rules) []*pb.Block
+}
+
+// mapGenerators are the generators rules can choose by name
+var mapGenerators = map[string]MapGenerator{
+	MapGeneratorUniform: uniformGenerator{},
+	MapGeneratorCity:    cityGenerator{},
+}
+
+type uniformGenerator struct{}
+
+func (uniformGenerator) Generate(rng *rand.Rand, rules *Rules) []*pb.Block {
 	gameMap := []*pb.Block{}
-	for y := int32(0); y < sideLen; y++ {
-		for x := int32(0); x < sideLen; x++ {
+	for y := int32(0); y < rules.Height; y++ {
+		for x := int32(0); x < rules.Width; x++ {
 			block := &pb.Block{
 				Position:   &pb.Coordintates{X: x, Y: y},
-				Type:       pb.BlockType(rand.Int31n(int32(len(pb.BlockType_name) - 1))),
-				Capacity:   rand.Int31n(5) + 1,
+				Type:       pb.BlockType(rng.Int31n(int32(len(pb.BlockType_name) - 1))),
+				Capacity:   rng.Int31n(5) + 1,
 				Connectors: []*pb.Connector{},
 			}
 
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultMode is used when the client doesn't ask for a mode. It is played
+// with the rules loaded by LoadRules.
+const DefaultMode = "classic"
+
+// Modes are the rule presets sessions can be created with, key: mode name
+type Modes map[string]*Rules
+
+// BuiltinModes returns the presets derived from the base rules
+func BuiltinModes(base *Rules) Modes {
+	quick := *base
+	quick.TimeLimitMin = 5
+	quick.MaxPlayers = 2
+
+	sandbox := *base
+	sandbox.UnlimitedMoney = true
+
+	return Modes{
+		DefaultMode: base,
+		"quick":     &quick,
+		"sandbox":   &sandbox,
+	}
+}
+
+// LoadModes reads the builtin modes extended by the modes file (if path is not
+// empty). The file is a json object of mode name to rules; each mode only lists
+// the fields that differ from the builtin mode with the same name or from the base rules.
+// The rules of every mode are validated.
+func LoadModes(base *Rules, path string) (Modes, error) {
+	modes := BuiltinModes(base)
+	if path != "" {
+		if err := modes.override(base, path); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(modes))
+	for name := range modes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := modes[name].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid mode %q rules: %w", name, err)
+		}
+	}
+
+	return modes, nil
+}
+
+// override changes and adds the modes listed in the modes file
+func (m Modes) override(base *Rules, path string) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read modes file error: %w", err)
+	}
+
+	overrides := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("parse modes file %s error: %w", path, err)
+	}
+
+	for name, override := range overrides {
+		if name == "" {
+			return fmt.Errorf("mode with empty name in modes file %s", path)
+		}
+
+		rules := *base
+		if builtin, ok := m[name]; ok {
+			rules = *builtin
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(override))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&rules); err != nil {
+			return fmt.Errorf("parse mode %q error: %w", name, err)
+		}
+		rules.applySideLen()
+
+		m[name] = &rules
+	}
+
+	return nil
+}
+
+// Get returns the rules of the mode, empty name means DefaultMode
+func (m Modes) Get(name string) (*Rules, error) {
+	if name == "" {
+		name = DefaultMode
+	}
+
+	rules, ok := m[name]
+	if !ok {
+		return nil, newError(KindInvalidArgument, ReasonUnknownMode, "mode", "unknown game mode %q", name)
+	}
+
+	return rules, nil
+}
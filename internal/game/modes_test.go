@@ -0,0 +1,54 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadModesValidatesEveryMode(t *testing.T) {
+	// one start area covers the whole map, so two players overlap
+	tiny := func() *Rules {
+		rules := DefaultRules()
+		rules.Width, rules.Height = 2, 2
+		rules.LicenseAreaSideLen = 2
+		return rules
+	}
+
+	tests := []struct {
+		name      string
+		base      *Rules
+		modesFile string // empty for no file
+		wantErr   string // empty for no error
+	}{
+		{name: "default rules", base: DefaultRules()},
+		{name: "builtin quick mode overlaps", base: tiny(), wantErr: `invalid mode "quick"`},
+		{name: "quick mode fixed by the file", base: tiny(), modesFile: `{"quick": {"maxPlayers": 1}}`},
+		{name: "invalid mode in the file", base: DefaultRules(), modesFile: `{"duel": {"maxPlayers": 0}}`, wantErr: `invalid mode "duel"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.modesFile != "" {
+				path = filepath.Join(t.TempDir(), "modes.json")
+				if err := os.WriteFile(path, []byte(tt.modesFile), 0o644); err != nil {
+					t.Fatalf("write modes file error: %v", err)
+				}
+			}
+
+			if err := tt.base.Validate(); err != nil {
+				t.Fatalf("base rules are invalid: %v", err)
+			}
+
+			_, err := LoadModes(tt.base, path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("LoadModes error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("LoadModes error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package game
+
+import "math/rand"
+
+// Random streams of a session. Each is derived from the session seed, so the
+// map and the course of the game are reproduced independently of each other.
+const (
+	randMap int64 = iota + 1
+	randGame
+)
+
+// newSeed returns a random session seed, zero is left for sessions stored before seeds
+func newSeed() int64 {
+	for {
+		if seed := rand.Int63(); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// sessionRand returns the random generator of the stream of the session with the seed
+func sessionRand(seed int64, stream int64) *rand.Rand {
+	return rand.New(rand.NewSource(int64(splitmix64(uint64(seed) + uint64(stream)*0x9e3779b97f4a7c15))))
+}
+
+// splitmix64 scrambles the bits, so the streams of close seeds are unrelated
+func splitmix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
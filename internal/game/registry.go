@@ -0,0 +1,60 @@
+package game
+
+import "sync"
+
+// runnerRegistry is the set of running games safe for concurrent use. Player
+// commands find the game by the user, so they don't go to the database.
+type runnerRegistry struct {
+	mutex   sync.RWMutex
+	runners map[int32]*GameRunner //key: sessionId
+	byUser  map[int32]*GameRunner //key: userId
+}
+
+func newRunnerRegistry() *runnerRegistry {
+	return &runnerRegistry{
+		runners: map[int32]*GameRunner{},
+		byUser:  map[int32]*GameRunner{},
+	}
+}
+
+func (r *runnerRegistry) get(sessionId int32) (*GameRunner, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	gameRunner, ok := r.runners[sessionId]
+	return gameRunner, ok
+}
+
+// getByUser returns the running game the user plays
+func (r *runnerRegistry) getByUser(userId int32) (*GameRunner, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	gameRunner, ok := r.byUser[userId]
+	return gameRunner, ok
+}
+
+func (r *runnerRegistry) add(gameRunner *GameRunner) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.runners[gameRunner.sessionId] = gameRunner
+	for _, userId := range gameRunner.userIds {
+		r.byUser[userId] = gameRunner
+	}
+}
+
+func (r *runnerRegistry) remove(gameRunner *GameRunner) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.runners[gameRunner.sessionId] == gameRunner {
+		delete(r.runners, gameRunner.sessionId)
+	}
+	// the players may have started a new game already
+	for _, userId := range gameRunner.userIds {
+		if r.byUser[userId] == gameRunner {
+			delete(r.byUser, userId)
+		}
+	}
+}
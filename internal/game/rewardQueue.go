@@ -5,51 +5,109 @@ import (
 	"time"
 )
 
+// Reward is the money a user gets once activationTime comes
 type Reward struct {
 	money          int32
 	activationTime time.Time
-	index          int
+	index          int // in the heap, -1 once the reward is popped or cancelled
 	userId         int32
 }
 
-type RewardQueue []*Reward
+// RewardQueue keeps the rewards until they are due, the earliest first.
+// It tracks the money each user is still to get.
+type RewardQueue struct {
+	items   rewardHeap
+	pending map[int32]int32 // key: userId
+}
+
+func NewRewardQueue() *RewardQueue {
+	return &RewardQueue{
+		items:   rewardHeap{},
+		pending: map[int32]int32{},
+	}
+}
+
+func (q *RewardQueue) Len() int { return len(q.items) }
+
+// Push schedules the reward
+func (q *RewardQueue) Push(reward *Reward) {
+	heap.Push(&q.items, reward)
+	q.pending[reward.userId] += reward.money
+}
+
+// Peek returns the reward which is due first without removing it
+func (q *RewardQueue) Peek() (*Reward, bool) {
+	if len(q.items) == 0 {
+		return nil, false
+	}
+	return q.items[0], true
+}
+
+// PopDue removes and returns the rewards due at now, the earliest first
+func (q *RewardQueue) PopDue(now time.Time) []*Reward {
+	due := []*Reward{}
+	for len(q.items) > 0 && !q.items[0].activationTime.After(now) {
+		reward := heap.Pop(&q.items).(*Reward)
+		q.forget(reward)
+		due = append(due, reward)
+	}
+	return due
+}
+
+// Cancel removes the reward if it is still queued and reports whether it was
+func (q *RewardQueue) Cancel(reward *Reward) bool {
+	if reward.index < 0 || reward.index >= len(q.items) || q.items[reward.index] != reward {
+		return false
+	}
 
-func (pq RewardQueue) Len() int { return len(pq) }
+	heap.Remove(&q.items, reward.index)
+	q.forget(reward)
+	return true
+}
+
+// Pending returns the money of the queued rewards of every user who has them
+func (q *RewardQueue) Pending() map[int32]int32 {
+	pending := make(map[int32]int32, len(q.pending))
+	for userId, money := range q.pending {
+		pending[userId] = money
+	}
+	return pending
+}
 
-func (pq RewardQueue) Less(i, j int) bool {
-	return pq[i].activationTime.Before(pq[j].activationTime)
+func (q *RewardQueue) forget(reward *Reward) {
+	q.pending[reward.userId] -= reward.money
+	if q.pending[reward.userId] == 0 {
+		delete(q.pending, reward.userId)
+	}
 }
 
-func (pq RewardQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
+// rewardHeap implements heap.Interface, use RewardQueue instead
+type rewardHeap []*Reward
+
+func (h rewardHeap) Len() int { return len(h) }
+
+func (h rewardHeap) Less(i, j int) bool {
+	return h[i].activationTime.Before(h[j].activationTime)
 }
 
-func (pq *RewardQueue) Push(x any) {
-	n := len(*pq)
+func (h rewardHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *rewardHeap) Push(x any) {
 	item := x.(*Reward)
-	item.index = n
-	*pq = append(*pq, item)
+	item.index = len(*h)
+	*h = append(*h, item)
 }
 
-func (pq *RewardQueue) Pop() any {
-	old := *pq
+func (h *rewardHeap) Pop() any {
+	old := *h
 	n := len(old)
 	item := old[n-1]
 	old[n-1] = nil  // avoid memory leak
 	item.index = -1 // for safety
-	*pq = old[0 : n-1]
+	*h = old[0 : n-1]
 	return item
 }
-
-func (pq *RewardQueue) Top() *Reward {
-	return (*pq)[len(*pq)-1]
-}
-
-// update modifies the priority and value of an Item in the queue.
-func (pq *RewardQueue) update(item *Reward, money int32, activationTime time.Time) {
-	item.money = money
-	item.activationTime = activationTime
-	heap.Fix(pq, item.index)
-}
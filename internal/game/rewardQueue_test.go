@@ -0,0 +1,270 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+var rewardsStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func rewardAt(userId, money int32, afterSec int) *Reward {
+	return &Reward{userId: userId, money: money, activationTime: rewardsStart.Add(time.Duration(afterSec) * time.Second)}
+}
+
+func TestRewardQueueOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		pushed    []int // activation seconds after rewardsStart
+		wantOrder []int
+	}{
+		{name: "in order", pushed: []int{1, 2, 3}, wantOrder: []int{1, 2, 3}},
+		{name: "reversed", pushed: []int{5, 3, 1}, wantOrder: []int{1, 3, 5}},
+		{name: "shuffled", pushed: []int{4, 1, 7, 2, 9, 3}, wantOrder: []int{1, 2, 3, 4, 7, 9}},
+		{name: "equal times", pushed: []int{2, 1, 2, 1, 2}, wantOrder: []int{1, 1, 2, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRewardQueue()
+			for _, sec := range tt.pushed {
+				q.Push(rewardAt(1, 1, sec))
+			}
+
+			due := q.PopDue(rewardsStart.Add(time.Hour))
+			if len(due) != len(tt.wantOrder) {
+				t.Fatalf("popped %d rewards, want %d", len(due), len(tt.wantOrder))
+			}
+			for i, reward := range due {
+				want := rewardsStart.Add(time.Duration(tt.wantOrder[i]) * time.Second)
+				if !reward.activationTime.Equal(want) {
+					t.Errorf("reward %d is due at %v, want %v", i, reward.activationTime, want)
+				}
+				if reward.index != -1 {
+					t.Errorf("popped reward %d has heap index %d, want -1", i, reward.index)
+				}
+			}
+			if q.Len() != 0 {
+				t.Errorf("%d rewards are left, want 0", q.Len())
+			}
+		})
+	}
+}
+
+func TestRewardQueuePopDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		pushed   []int
+		nowSec   int
+		wantDue  int
+		wantLeft int
+	}{
+		{name: "empty", nowSec: 10},
+		{name: "before the first", pushed: []int{5, 10}, nowSec: 4, wantLeft: 2},
+		{name: "exactly at now", pushed: []int{5, 10}, nowSec: 5, wantDue: 1, wantLeft: 1},
+		{name: "between", pushed: []int{5, 10, 15}, nowSec: 12, wantDue: 2, wantLeft: 1},
+		{name: "equal times at now", pushed: []int{5, 5, 5, 6}, nowSec: 5, wantDue: 3, wantLeft: 1},
+		{name: "after all", pushed: []int{5, 10}, nowSec: 100, wantDue: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRewardQueue()
+			for _, sec := range tt.pushed {
+				q.Push(rewardAt(1, 1, sec))
+			}
+
+			now := rewardsStart.Add(time.Duration(tt.nowSec) * time.Second)
+			due := q.PopDue(now)
+			if len(due) != tt.wantDue {
+				t.Errorf("popped %d rewards, want %d", len(due), tt.wantDue)
+			}
+			for _, reward := range due {
+				if reward.activationTime.After(now) {
+					t.Errorf("reward due at %v is popped at %v", reward.activationTime, now)
+				}
+			}
+			if q.Len() != tt.wantLeft {
+				t.Errorf("%d rewards are left, want %d", q.Len(), tt.wantLeft)
+			}
+			if next, ok := q.Peek(); ok && !next.activationTime.After(now) {
+				t.Errorf("reward due at %v is left at %v", next.activationTime, now)
+			}
+		})
+	}
+}
+
+func TestRewardQueuePeek(t *testing.T) {
+	q := NewRewardQueue()
+	if reward, ok := q.Peek(); ok || reward != nil {
+		t.Fatalf("Peek on an empty queue = %v, %v, want nil, false", reward, ok)
+	}
+
+	q.Push(rewardAt(1, 1, 7))
+	q.Push(rewardAt(1, 1, 3))
+	reward, ok := q.Peek()
+	if !ok || !reward.activationTime.Equal(rewardsStart.Add(3*time.Second)) {
+		t.Fatalf("Peek = %v, %v, want the reward due in 3s", reward, ok)
+	}
+	if q.Len() != 2 {
+		t.Errorf("Peek removed a reward, %d are left, want 2", q.Len())
+	}
+}
+
+func TestRewardQueueCancel(t *testing.T) {
+	tests := []struct {
+		name string
+		// cancel gets the queue with rewards due in 1s, 2s and 3s and
+		// returns the results of its Cancel calls
+		cancel   func(q *RewardQueue, rewards []*Reward) []bool
+		want     []bool
+		wantLeft []int
+	}{
+		{
+			name: "queued",
+			cancel: func(q *RewardQueue, rewards []*Reward) []bool {
+				return []bool{q.Cancel(rewards[1])}
+			},
+			want:     []bool{true},
+			wantLeft: []int{1, 3},
+		},
+		{
+			name: "the first",
+			cancel: func(q *RewardQueue, rewards []*Reward) []bool {
+				return []bool{q.Cancel(rewards[0])}
+			},
+			want:     []bool{true},
+			wantLeft: []int{2, 3},
+		},
+		{
+			name: "already popped",
+			cancel: func(q *RewardQueue, rewards []*Reward) []bool {
+				q.PopDue(rewardsStart.Add(time.Second))
+				return []bool{q.Cancel(rewards[0])}
+			},
+			want:     []bool{false},
+			wantLeft: []int{2, 3},
+		},
+		{
+			name: "twice",
+			cancel: func(q *RewardQueue, rewards []*Reward) []bool {
+				return []bool{q.Cancel(rewards[2]), q.Cancel(rewards[2])}
+			},
+			want:     []bool{true, false},
+			wantLeft: []int{1, 2},
+		},
+		{
+			name: "never pushed",
+			cancel: func(q *RewardQueue, rewards []*Reward) []bool {
+				return []bool{q.Cancel(rewardAt(1, 1, 1))}
+			},
+			want:     []bool{false},
+			wantLeft: []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRewardQueue()
+			rewards := []*Reward{rewardAt(1, 1, 1), rewardAt(1, 1, 2), rewardAt(1, 1, 3)}
+			for _, reward := range rewards {
+				q.Push(reward)
+			}
+
+			got := tt.cancel(q, rewards)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Cancel results %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Cancel results %v, want %v", got, tt.want)
+					break
+				}
+			}
+
+			left := q.PopDue(rewardsStart.Add(time.Hour))
+			if len(left) != len(tt.wantLeft) {
+				t.Fatalf("%d rewards are left, want %d", len(left), len(tt.wantLeft))
+			}
+			for i, reward := range left {
+				want := rewardsStart.Add(time.Duration(tt.wantLeft[i]) * time.Second)
+				if !reward.activationTime.Equal(want) {
+					t.Errorf("left reward %d is due at %v, want %v", i, reward.activationTime, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRewardQueuePending(t *testing.T) {
+	tests := []struct {
+		name string
+		// change gets the queue with user 1 rewards of 10 in 1s and 20 in 2s
+		// and a user 2 reward of 5 in 3s
+		change func(q *RewardQueue, rewards []*Reward)
+		want   map[int32]int32
+	}{
+		{
+			name:   "pushed",
+			change: func(q *RewardQueue, rewards []*Reward) {},
+			want:   map[int32]int32{1: 30, 2: 5},
+		},
+		{
+			name: "popped",
+			change: func(q *RewardQueue, rewards []*Reward) {
+				q.PopDue(rewardsStart.Add(time.Second))
+			},
+			want: map[int32]int32{1: 20, 2: 5},
+		},
+		{
+			name: "cancelled",
+			change: func(q *RewardQueue, rewards []*Reward) {
+				q.Cancel(rewards[1])
+			},
+			want: map[int32]int32{1: 10, 2: 5},
+		},
+		{
+			name: "cancelled twice",
+			change: func(q *RewardQueue, rewards []*Reward) {
+				q.Cancel(rewards[1])
+				q.Cancel(rewards[1])
+			},
+			want: map[int32]int32{1: 10, 2: 5},
+		},
+		{
+			name: "user total hits zero",
+			change: func(q *RewardQueue, rewards []*Reward) {
+				q.Cancel(rewards[2])
+			},
+			want: map[int32]int32{1: 30},
+		},
+		{
+			name: "all popped",
+			change: func(q *RewardQueue, rewards []*Reward) {
+				q.PopDue(rewardsStart.Add(time.Hour))
+			},
+			want: map[int32]int32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRewardQueue()
+			rewards := []*Reward{rewardAt(1, 10, 1), rewardAt(1, 20, 2), rewardAt(2, 5, 3)}
+			for _, reward := range rewards {
+				q.Push(reward)
+			}
+
+			tt.change(q, rewards)
+			got := q.Pending()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Pending() = %v, want %v", got, tt.want)
+			}
+			for userId, money := range tt.want {
+				if got[userId] != money {
+					t.Errorf("Pending() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
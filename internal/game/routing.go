@@ -0,0 +1,136 @@
+package game
+
+import (
+	"container/heap"
+	"time"
+)
+
+// line is what the passenger rides: the transport of a player. Changing the
+// line is a transfer.
+type line struct {
+	userId    int32
+	transport int32
+}
+
+// routeState is the passenger at the block arrived by the line after the hops
+type routeState struct {
+	block Coords
+	line  line
+	hops  int
+}
+
+type routeItem struct {
+	state routeState
+	cost  float64
+}
+
+type routeQueue []routeItem
+
+func (q routeQueue) Len() int { return len(q) }
+
+func (q routeQueue) Less(i, j int) bool {
+	return q[i].cost < q[j].cost
+}
+
+func (q routeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *routeQueue) Push(x any) {
+	*q = append(*q, x.(routeItem))
+}
+
+func (q *routeQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[0 : n-1]
+	return item
+}
+
+// noLine is the line of the passenger who hasn't got on yet
+var noLine = line{transport: -1}
+
+// hopCost is how much the passenger dislikes the hop: its travel time in
+// seconds, the fare weighted by rules.FareWeight and the transfer penalty
+// if the passenger changes the line
+func hopCost(rules *Rules, from Coords, hop *Destination, prev line) float64 {
+	distance := hopDistance(from, hop.To)
+
+	cost := distance * rules.transportDuration(hop.Transport).Seconds()
+	cost += distance * float64(rules.transportReward(hop.Transport)) * rules.FareWeight
+	if prev != noLine && (line{userId: hop.UserId, transport: int32(hop.Transport)}) != prev {
+		cost += time.Duration(rules.TransferPenalty).Seconds()
+	}
+
+	return cost
+}
+
+// BestPath returns the path the passenger prefers: the cheapest by hopCost
+// of the ones with at most rules.PassengerFuel hops, false if there is none.
+// The routes out of service at the moment are skipped.
+func (tn *TransportNetwork) BestPath(rules *Rules, now time.Time, from Coords, to Coords) (Path, bool) {
+	start := routeState{block: from, line: noLine}
+	costs := map[routeState]float64{start: 0}
+	prev := map[routeState]routeState{}
+	via := map[routeState]*Destination{}
+
+	queue := &routeQueue{{state: start}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(routeItem)
+		if item.cost > costs[item.state] {
+			continue // outdated
+		}
+
+		cur := item.state
+		if cur.block == to {
+			return buildPath(from, cur, prev, via)
+		}
+		if cur.hops >= rules.PassengerFuel {
+			continue // out of fuel
+		}
+
+		for _, hop := range tn.blocks[cur.block] {
+			if !hop.inService(now) {
+				continue
+			}
+			next := routeState{block: hop.To, line: line{userId: hop.UserId, transport: int32(hop.Transport)}, hops: cur.hops + 1}
+			cost := item.cost + hopCost(rules, cur.block, hop, cur.line)
+
+			if known, ok := costs[next]; ok && known <= cost {
+				continue
+			}
+			costs[next] = cost
+			prev[next] = cur
+			via[next] = hop
+			heap.Push(queue, routeItem{state: next, cost: cost})
+		}
+	}
+
+	return Path{}, false
+}
+
+func buildPath(from Coords, end routeState, prev map[routeState]routeState, via map[routeState]*Destination) (Path, bool) {
+	hops := []*Destination{}
+	for cur := end; ; cur = prev[cur] {
+		hop, ok := via[cur]
+		if !ok {
+			break
+		}
+		hops = append(hops, hop)
+	}
+
+	if len(hops) == 0 {
+		return Path{}, false
+	}
+
+	for i, j := 0, len(hops)-1; i < j; i, j = i+1, j-1 {
+		hops[i], hops[j] = hops[j], hops[i]
+	}
+
+	return Path{
+		Start: from,
+		Hops:  hops,
+	}, true
+}
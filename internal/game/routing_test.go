@@ -0,0 +1,155 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"testing"
+	"time"
+)
+
+// testRoute is a route of the test network
+type testRoute struct {
+	from, to  Coords
+	userId    int32
+	transport pb.Transport
+	inService time.Duration // after now
+}
+
+func TestBestPath(t *testing.T) {
+	// a chain of bus routes of user 1 from (0, 0) to (4, 0) and two metro
+	// routes through (2, 1)
+	chain := []testRoute{
+		{from: Coords{0, 0}, to: Coords{1, 0}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{1, 0}, to: Coords{2, 0}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{2, 0}, to: Coords{3, 0}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{3, 0}, to: Coords{4, 0}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{0, 0}, to: Coords{2, 1}, userId: 1, transport: pb.Transport_METRO},
+		{from: Coords{2, 1}, to: Coords{4, 0}, userId: 1, transport: pb.Transport_METRO},
+	}
+	// from (0, 0) to (2, 0): straight with a change of the line at (1, 0) or
+	// round through (0, 1) and (2, 1) by one line
+	transfer := []testRoute{
+		{from: Coords{0, 0}, to: Coords{1, 0}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{1, 0}, to: Coords{2, 0}, userId: 2, transport: pb.Transport_BUS},
+		{from: Coords{0, 0}, to: Coords{0, 1}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{0, 1}, to: Coords{2, 1}, userId: 1, transport: pb.Transport_BUS},
+		{from: Coords{2, 1}, to: Coords{2, 0}, userId: 1, transport: pb.Transport_BUS},
+	}
+	// from (0, 0) to (2, 0): a direct metro or a bus through (1, 1)
+	fare := func(metroInService time.Duration) []testRoute {
+		return []testRoute{
+			{from: Coords{0, 0}, to: Coords{2, 0}, userId: 1, transport: pb.Transport_METRO, inService: metroInService},
+			{from: Coords{0, 0}, to: Coords{1, 1}, userId: 1, transport: pb.Transport_BUS},
+			{from: Coords{1, 1}, to: Coords{2, 0}, userId: 1, transport: pb.Transport_BUS},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		routes   []testRoute
+		rules    func(r *Rules)
+		to       Coords
+		wantHops []Coords // nil if there is no path
+	}{
+		{
+			name:     "no transfer penalty",
+			routes:   transfer,
+			rules:    func(r *Rules) { r.TransferPenalty = 0 },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{1, 0}, {2, 0}},
+		},
+		{
+			name:     "transfer penalty",
+			routes:   transfer,
+			rules:    func(r *Rules) { r.TransferPenalty = Duration(10 * time.Second) },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{0, 1}, {2, 1}, {2, 0}},
+		},
+		{
+			name:     "fare ignored",
+			routes:   fare(0),
+			rules:    func(r *Rules) { r.FareWeight = 0 },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{2, 0}},
+		},
+		{
+			name:     "fare weight",
+			routes:   fare(0),
+			rules:    func(r *Rules) { r.FareWeight = 1 },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{1, 1}, {2, 0}},
+		},
+		{
+			name:     "back in service",
+			routes:   fare(-time.Second),
+			rules:    func(r *Rules) { r.FareWeight = 0 },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{2, 0}},
+		},
+		{
+			name:     "out of service",
+			routes:   fare(time.Minute),
+			rules:    func(r *Rules) { r.FareWeight = 0 },
+			to:       Coords{2, 0},
+			wantHops: []Coords{{1, 1}, {2, 0}},
+		},
+		{
+			name:     "enough fuel for the cheapest",
+			routes:   chain,
+			rules:    func(r *Rules) { r.PassengerFuel = 4 },
+			to:       Coords{4, 0},
+			wantHops: []Coords{{1, 0}, {2, 0}, {3, 0}, {4, 0}},
+		},
+		{
+			name:     "hop limit",
+			routes:   chain,
+			rules:    func(r *Rules) { r.PassengerFuel = 3 },
+			to:       Coords{4, 0},
+			wantHops: []Coords{{2, 1}, {4, 0}},
+		},
+		{
+			name:   "out of fuel",
+			routes: chain,
+			rules:  func(r *Rules) { r.PassengerFuel = 1 },
+			to:     Coords{4, 0},
+		},
+		{
+			name:   "unreachable",
+			routes: chain,
+			rules:  func(r *Rules) {},
+			to:     Coords{5, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			rules := DefaultRules()
+			tt.rules(rules)
+
+			tn := NewTransportNetwork()
+			for _, route := range tt.routes {
+				if err := tn.ConnectBlocks(route.userId, route.from, route.to, route.transport); err != nil {
+					t.Fatalf("connect blocks error: %v", err)
+				}
+				if route.inService != 0 {
+					if err := tn.UpgradeRoute(route.from, route.to, route.transport, now.Add(route.inService)); err != nil {
+						t.Fatalf("upgrade route error: %v", err)
+					}
+				}
+			}
+
+			path, ok := tn.BestPath(rules, now, Coords{0, 0}, tt.to)
+			if ok != (tt.wantHops != nil) {
+				t.Fatalf("path found = %v, want %v", ok, tt.wantHops != nil)
+			}
+			if len(path.Hops) != len(tt.wantHops) {
+				t.Fatalf("path has %d hops, want %v", len(path.Hops), tt.wantHops)
+			}
+			for i, hop := range path.Hops {
+				if hop.To != tt.wantHops[i] {
+					t.Errorf("hop %d leads to %v, want %v", i, hop.To, tt.wantHops[i])
+				}
+			}
+		})
+	}
+}
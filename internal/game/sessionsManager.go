@@ -1,7 +1,7 @@
 package game
 
 import (
-	"fmt"
+	"errors"
 	pb "game_server/api/v1"
 	"game_server/internal/database"
 	"log"
@@ -10,50 +10,98 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type SessionsManager struct {
-	db                   *database.DbConnector
-	pendingSessions      []int32
-	gameRuners           map[int32]*GameRunner //key: sessionId
-	pendingSessionsMutex sync.Mutex
-	transportMutex       sync.Mutex
-	moneyMutex           *sync.Mutex
+	db               database.SessionStore
+	modes            Modes
+	maps             Maps
+	streamOptions    StreamOptions
+	pendingSessions  map[string][]int32 //key: SessionOptions.pendingKey()
+	gameRuners       *runnerRegistry
+	matchmakingMutex sync.Mutex // guards pendingSessions and joining sessions
 }
 
-func NewSessionsManager(db *database.DbConnector) *SessionsManager {
+// SessionOptions are what the player asks of a new session
+type SessionOptions struct {
+	Mode string // empty means DefaultMode
+	Map  string // name of a hand-authored map, empty for a generated one
+	Seed int64  // reproduces the map and the game, 0 for a random one
+}
+
+// pendingKey groups the pending sessions, players join only the sessions
+// created with the same options
+func (o SessionOptions) pendingKey() string {
+	return o.Mode + "/" + o.Map + "/" + strconv.FormatInt(o.Seed, 10)
+}
+
+func NewSessionsManager(db database.SessionStore, modes Modes, maps Maps, streamOptions StreamOptions) *SessionsManager {
 	return &SessionsManager{
 		db:              db,
-		pendingSessions: []int32{},
-		gameRuners:      map[int32]*GameRunner{},
-		moneyMutex:      &sync.Mutex{},
+		modes:           modes,
+		maps:            maps,
+		streamOptions:   streamOptions,
+		pendingSessions: map[string][]int32{},
+		gameRuners:      newRunnerRegistry(),
 	}
 }
 
-func (sm *SessionsManager) FindSessionForUser(userId int32) (*pb.Session, error) {
-	pendingSession, err := sm.getPendingSession()
+// FindSessionForUser adds the user to a pending session created with the same
+// options or creates a new one
+func (sm *SessionsManager) FindSessionForUser(userId int32, options SessionOptions) (*pb.Session, error) {
+	if options.Mode == "" {
+		options.Mode = DefaultMode
+	}
+
+	rules, err := sm.modes.Get(options.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapFile *MapFile
+	if options.Map != "" {
+		if mapFile, err = sm.maps.Get(options.Map); err != nil {
+			return nil, err
+		}
+		if len(mapFile.StartAreas) < rules.MaxPlayers {
+			return nil, newError(KindInvalidArgument, ReasonTooFewStartAreas, "map", "map %q has %d start areas, mode %q needs %d", options.Map, len(mapFile.StartAreas), options.Mode, rules.MaxPlayers)
+		}
+	}
+	areas := startAreas(rules, mapFile)
+
+	sm.matchmakingMutex.Lock()
+	defer sm.matchmakingMutex.Unlock()
+
+	// the user may have joined a session by a concurrent request
+	if session, err := sm.db.GetAliveSessionByUser(userId); err == nil {
+		return session, nil
+	} else if !errors.Is(err, database.ErrSessionNotFound) {
+		return nil, err
+	}
+
+	key := options.pendingKey()
+	pendingSession, err := sm.getPendingSession(key, rules)
 	if err != nil {
 		return nil, err
 	}
 
 	if pendingSession == nil {
-		session := createSession()
-		user := createUser(userId, 0)
+		session := createSession(rules, options, mapFile)
+		user := createUser(rules, userId, areas[0])
 		session.Users = append(session.Users, user)
 
-		if maxPlayers == 1 {
+		if rules.MaxPlayers == 1 {
 			if err := sm.db.AddSession(session); err != nil {
 				return nil, err
 			}
 
-			sm.startSesison(session)
-
-			return session, sm.db.UpdateSession(session)
+			return session, sm.startSesison(session, rules)
 		}
 
-		sm.addPendingSession(session.Id)
+		sm.addPendingSession(key, session.Id)
 
 		if err := sm.db.AddSession(session); err != nil {
 			return nil, err
@@ -62,11 +110,11 @@ func (sm *SessionsManager) FindSessionForUser(userId int32) (*pb.Session, error)
 		return session, nil
 	}
 
-	user := createUser(userId, len(pendingSession.Users))
+	user := createUser(rules, userId, areas[len(pendingSession.Users)])
 	pendingSession.Users = append(pendingSession.Users, user)
 
-	if len(pendingSession.Users) == maxPlayers {
-		sm.startSesison(pendingSession)
+	if len(pendingSession.Users) == rules.MaxPlayers {
+		return pendingSession, sm.startSesison(pendingSession, rules)
 	}
 
 	if err := sm.db.UpdateSession(pendingSession); err != nil {
@@ -76,68 +124,96 @@ func (sm *SessionsManager) FindSessionForUser(userId int32) (*pb.Session, error)
 	return pendingSession, nil
 }
 
-func (sm *SessionsManager) startSesison(session *pb.Session) {
+// GetSessionForUser returns the session the user plays or finds a new one with the options
+func (sm *SessionsManager) GetSessionForUser(userId int32, options SessionOptions) (*pb.Session, error) {
+	session, err := sm.db.GetAliveSessionByUser(userId)
+	if errors.Is(err, database.ErrSessionNotFound) {
+		return sm.FindSessionForUser(userId, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("found exists session %d for user %d\n", session.Id, userId)
+
+	// the game runner has more recent state than the database
+	if gameRunner, ok := sm.gameRuners.get(session.Id); ok {
+		if liveSession, err := gameRunner.snapshot(); err == nil {
+			return liveSession, nil
+		}
+	}
+
+	return session, nil
+}
+
+// Rules returns the rules of the mode, empty mode means DefaultMode
+func (sm *SessionsManager) Rules(mode string) (*Rules, error) {
+	return sm.modes.Get(mode)
+}
+
+// startSesison persists the session as active and hands a copy of it to the new game runner
+func (sm *SessionsManager) startSesison(session *pb.Session, rules *Rules) error {
 	session.Status = pb.SessionStatus_ACTIVE
 	session.StartTime = timestamppb.New(time.Now().Add(time.Second * 30))
 
-	gameRunner := NewGameRunner(session.Id, sm.db, session, sm.moneyMutex)
+	if err := sm.db.UpdateSession(session); err != nil {
+		return err
+	}
+
+	gameRunner := NewGameRunner(sm.db, rules, sm.streamOptions, proto.Clone(session).(*pb.Session))
 	gameRunner.startGameComputation()
 
-	sm.gameRuners[session.Id] = gameRunner
+	sm.gameRuners.add(gameRunner)
+	go func() {
+		<-gameRunner.ctx.Done()
+		sm.gameRuners.remove(gameRunner)
+	}()
+
+	return nil
 }
 
-func createSession() *pb.Session {
+// createSession creates the session on the hand-authored map if it is not
+// nil or on a generated one
+func createSession(rules *Rules, options SessionOptions, mapFile *MapFile) *pb.Session {
+	seed := options.Seed
+	if seed == 0 {
+		seed = newSeed()
+	}
+
 	session := &pb.Session{
 		Id:        rand.Int31(),
+		Mode:      options.Mode,
+		MapName:   options.Map,
 		Users:     []*pb.User{},
-		Map:       generateMap(),
-		TimeLimit: durationpb.New(time.Duration(TimeLimitMin) * time.Minute),
+		Width:     rules.Width,
+		Height:    rules.Height,
+		Seed:      seed,
+		TimeLimit: durationpb.New(rules.TimeLimit()),
 		Status:    pb.SessionStatus_WAITING,
 	}
+
+	if mapFile != nil {
+		session.Width, session.Height = mapFile.Width, mapFile.Height
+		session.Map = mapFile.NewBlocks()
+	} else {
+		session.Map = mapGenerators[rules.MapGenerator].Generate(sessionRand(seed, randMap), rules)
+	}
+
 	return session
 }
 
-// Start positions;
-// ┌--------┐
-// |   0    |
-// |3      1|
-// |   2    |
-// └--------┘
-func createUser(userId int32, startPos int) *pb.User {
-	license := []*pb.Coordintates{}
-	start := sideLen/2 - licenseAreaSideLen/2
-	end := sideLen/2 + licenseAreaSideLen/2
-
-	var xStart int32 = 0
-	var xEnd int32 = 0
-	var yStart int32 = 0
-	var yEnd int32 = 0
-
-	switch startPos {
-	case 0:
-		xStart = start
-		xEnd = end
-		yEnd = licenseAreaSideLen
-	case 1:
-		xStart = sideLen - licenseAreaSideLen
-		xEnd = sideLen
-		yStart = start
-		yEnd = end
-	case 2:
-		xStart = start
-		xEnd = end
-		yStart = sideLen - licenseAreaSideLen
-		yEnd = sideLen
-	case 3:
-		xEnd = licenseAreaSideLen
-		yStart = start
-		yEnd = end
-	default:
-		log.Panicf("unexpected starting pos %d during user creation", startPos)
-	}
-
-	for y := yStart; y < yEnd; y++ {
-		for x := xStart; x < xEnd; x++ {
+// startAreas returns the license areas of the players in the order they join
+func startAreas(rules *Rules, mapFile *MapFile) []Area {
+	if mapFile != nil {
+		return mapFile.StartAreas
+	}
+	return generatedStartAreas(rules)
+}
+
+// createUser creates the player with the license of the area
+func createUser(rules *Rules, userId int32, area Area) *pb.User {
+	license := make([]*pb.Coordintates, 0, area.Width*area.Height)
+	for y := area.Y; y < area.Y+area.Height; y++ {
+		for x := area.X; x < area.X+area.Width; x++ {
 			license = append(license, &pb.Coordintates{X: x, Y: y})
 		}
 	}
@@ -145,120 +221,135 @@ func createUser(userId int32, startPos int) *pb.User {
 	user := pb.User{
 		Id:      userId,
 		Name:    strconv.Itoa(int(userId)),
-		Money:   startMoney,
+		Money:   rules.StartMoney,
 		License: license,
 	}
 	return &user
 }
 
-func (sm *SessionsManager) addPendingSession(sessionId int32) {
-	sm.pendingSessionsMutex.Lock()
-	defer sm.pendingSessionsMutex.Unlock()
-
-	sm.pendingSessions = append(sm.pendingSessions, sessionId)
+func (sm *SessionsManager) addPendingSession(key string, sessionId int32) {
+	sm.pendingSessions[key] = append(sm.pendingSessions[key], sessionId)
 }
 
-func (sm *SessionsManager) getPendingSession() (*pb.Session, error) {
-	sm.pendingSessionsMutex.Lock()
-	defer sm.pendingSessionsMutex.Unlock()
-
-	if len(sm.pendingSessions) == 0 {
+func (sm *SessionsManager) getPendingSession(key string, rules *Rules) (*pb.Session, error) {
+	pendingSessions := sm.pendingSessions[key]
+	if len(pendingSessions) == 0 {
 		return nil, nil
 	}
 
-	id := sm.pendingSessions[0]
+	id := pendingSessions[0]
 	session, err := sm.db.GetSession(id)
 	if err != nil {
-		sm.pendingSessions = sm.pendingSessions[1:]
+		sm.pendingSessions[key] = pendingSessions[1:]
 		return nil, err
 	}
 
-	if len(session.Users) >= maxPlayers-1 {
-		sm.pendingSessions = sm.pendingSessions[1:]
+	if len(session.Users) >= rules.MaxPlayers-1 {
+		sm.pendingSessions[key] = pendingSessions[1:]
 	}
 
 	return session, nil
 }
 
 func (sm *SessionsManager) AddTransport(userId int32, from *pb.Coordintates, to *pb.Coordintates, transport pb.Transport) error {
-	sm.moneyMutex.Lock()
-	sm.transportMutex.Lock()
-	defer sm.moneyMutex.Unlock()
-	defer sm.transportMutex.Unlock()
-
-	session, err := sm.db.GetAliveSessionByUser(userId)
+	gameRunner, err := sm.getGameRunner(userId)
 	if err != nil {
 		return err
 	}
-	log.Printf("found session %d for user %d\n", session.Id, userId)
 
-	fromBlock := session.Map[from.Y*sideLen+from.X]
-	toBlock := session.Map[to.Y*sideLen+to.X]
+	return gameRunner.execute(&buildTransportCmd{
+		userId:    userId,
+		from:      from,
+		to:        to,
+		transport: transport,
+	})
+}
 
-	if len(fromBlock.Connectors) == int(fromBlock.Capacity) || len(toBlock.Connectors) == int(toBlock.Capacity) {
-		return fmt.Errorf("connector capacity exceeded")
+// RemoveTransport demolishes the user's route between the blocks and refunds a share of its cost
+func (sm *SessionsManager) RemoveTransport(userId int32, from *pb.Coordintates, to *pb.Coordintates) error {
+	gameRunner, err := sm.getGameRunner(userId)
+	if err != nil {
+		return err
 	}
 
-	fromBlock.Connectors = append(fromBlock.Connectors, &pb.Connector{UserId: userId, Transport: transport, Destination: to})
-	toBlock.Connectors = append(toBlock.Connectors, &pb.Connector{UserId: userId, Transport: transport, Destination: from})
-
-	gameRunner, ok := sm.gameRuners[session.Id]
-	if !ok {
-		return fmt.Errorf("no game runner for session %d with user: %d", session.Id, userId)
-	}
+	return gameRunner.execute(&removeTransportCmd{
+		userId: userId,
+		from:   from,
+		to:     to,
+	})
+}
 
-	if err := gameRunner.extendNetwork(userId, from, to, transport); err != nil {
+// UpgradeTransport changes the transport of the user's route between the blocks for the cost difference
+func (sm *SessionsManager) UpgradeTransport(userId int32, from *pb.Coordintates, to *pb.Coordintates, transport pb.Transport) error {
+	gameRunner, err := sm.getGameRunner(userId)
+	if err != nil {
 		return err
 	}
 
-	for _, user := range session.Users {
-		if user.Id == userId {
-			money := user.Money - transportCost(transport)
-			if money < 0 {
-				return fmt.Errorf("user %d does not have enough money", userId)
-			}
-			user.Money = money
-			break
-		}
-	}
-
-	return sm.db.UpdateSession(session)
+	return gameRunner.execute(&upgradeTransportCmd{
+		userId:    userId,
+		from:      from,
+		to:        to,
+		transport: transport,
+	})
 }
 
 func (sm *SessionsManager) ExtendLicense(userId int32, blocks []*pb.Coordintates) error {
-	sm.moneyMutex.Lock()
-	defer sm.moneyMutex.Unlock()
-
-	session, err := sm.db.GetAliveSessionByUser(userId)
+	gameRunner, err := sm.getGameRunner(userId)
 	if err != nil {
 		return err
 	}
-	log.Printf("found session %d for user %d\n", session.Id, userId)
 
-	for _, user := range session.Users {
-		if user.Id == userId {
-			money := user.Money - LicenseCost*int32(len(blocks))
-			if money < 0 {
-				return fmt.Errorf("user %d does not have enough money", userId)
-			}
+	return gameRunner.execute(&extendLicenseCmd{
+		userId: userId,
+		blocks: blocks,
+	})
+}
 
-			user.License = append(user.License, blocks...)
-			user.Money = money
-			break
-		}
+// StreamState sends the session states to srv until the client leaves or the
+// game ends. The states after lastSeq are sent first if the client reconnects.
+func (sm *SessionsManager) StreamState(sessionId, userId int32, lastSeq int64, srv pb.Api_StateStreamServer) error {
+	session, err := sm.db.GetSession(sessionId)
+	if err != nil {
+		return sessionNotFound(err, "session %d not found", sessionId)
 	}
 
-	return sm.db.UpdateSession(session)
-}
+	if findUser(session, userId) == nil {
+		return newError(KindPermissionDenied, ReasonNotPlayer, "sessionId", "user %d is not a player of session %d", userId, sessionId)
+	}
 
-func (sm *SessionsManager) StreamState(sessionId, userId int32, srv pb.Api_StateStreamServer) error {
-	gameRunner, ok := sm.gameRuners[sessionId]
+	gameRunner, ok := sm.gameRuners.get(sessionId)
 	if !ok {
-		return fmt.Errorf("no game runner for session %d with user: %d", sessionId, userId)
+		return newError(KindFailedPrecondition, ReasonSessionNotActive, "sessionId", "session %d is not running", sessionId)
+	}
+
+	return gameRunner.subscribe(srv, lastSeq)
+}
+
+// getGameRunner returns the runner of the session the user plays right now.
+// Only the users who don't play go to the database to learn why.
+func (sm *SessionsManager) getGameRunner(userId int32) (*GameRunner, error) {
+	if gameRunner, ok := sm.gameRuners.getByUser(userId); ok {
+		return gameRunner, nil
 	}
 
-	ctx := gameRunner.addConnection(srv)
-	<-ctx.Done()
+	session, err := sm.db.GetAliveSessionByUser(userId)
+	if err != nil {
+		return nil, sessionNotFound(err, "user %d has no session", userId)
+	}
 
+	if session.Status == pb.SessionStatus_WAITING {
+		return nil, newError(KindFailedPrecondition, ReasonSessionNotActive, "", "session %d is waiting for players", session.Id)
+	}
+	// e.g. the active session of the server run before the restart
+	return nil, newError(KindFailedPrecondition, ReasonSessionNotActive, "", "session %d is active but not running", session.Id)
+}
+
+func findUser(session *pb.Session, userId int32) *pb.User {
+	for _, user := range session.Users {
+		if user.Id == userId {
+			return user
+		}
+	}
 	return nil
 }
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"context"
+	"errors"
+	pb "game_server/api/v1"
+	"game_server/internal/database"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeStream is a client state stream which reads every state at once
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent atomic.Int64
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) Send(state *pb.State) error {
+	// reads the whole state like grpc does
+	if _, err := proto.Marshal(state); err != nil {
+		return err
+	}
+	s.sent.Add(1)
+	return nil
+}
+
+// TestConcurrentSessions plays many sessions at once: players are matched,
+// build, extend their licenses and open and close state streams from their own
+// goroutines. It is meant to be run with -race.
+func TestConcurrentSessions(t *testing.T) {
+	const players = 200
+	duration := time.Second
+	if testing.Short() {
+		duration = 300 * time.Millisecond
+	}
+
+	rules := DefaultRules()
+	rules.MaxPlayers = 2
+	rules.UnlimitedMoney = true
+	rules.MapGenerator = MapGeneratorUniform
+	sm := NewSessionsManager(database.NewMemoryStore(), Modes{DefaultMode: rules}, Maps{}, DefaultStreamOptions())
+
+	var wg sync.WaitGroup
+	for userId := int32(1); userId <= players; userId++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			session, err := sm.FindSessionForUser(userId, SessionOptions{})
+			if err != nil {
+				t.Errorf("find session for user %d error: %v", userId, err)
+				return
+			}
+			user := findUser(session, userId)
+
+			deadline := time.Now().Add(duration)
+			for i := 0; time.Now().Before(deadline); i++ {
+				// the session may still wait for the second player, the
+				// commands are rejected then
+				_ = sm.AddTransport(userId, user.License[0], user.License[1+i%(len(user.License)-1)], pb.Transport_BUS)
+				_ = sm.ExtendLicense(userId, []*pb.Coordintates{{X: int32(i) % rules.Width, Y: 0}})
+
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+				_ = sm.StreamState(session.Id, userId, int64(i%3), &fakeStream{ctx: ctx})
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	joined := map[int32]int{}
+	for userId := int32(1); userId <= players; userId++ {
+		session, err := sm.GetSessionForUser(userId, SessionOptions{})
+		if err != nil {
+			t.Fatalf("get session for user %d error: %v", userId, err)
+		}
+		if len(session.Users) != rules.MaxPlayers {
+			t.Errorf("session %d has %d players, want %d", session.Id, len(session.Users), rules.MaxPlayers)
+		}
+		joined[session.Id]++
+	}
+	for sessionId, n := range joined {
+		if n != rules.MaxPlayers {
+			t.Errorf("session %d is played by %d users, want %d", sessionId, n, rules.MaxPlayers)
+		}
+	}
+}
+
+// BenchmarkConcurrentSessions measures player commands of many sessions
+// played at the same time, every command goes through the whole pipeline
+//
+//	go test -bench ConcurrentSessions -cpu 1,4,8 ./internal/game/
+func BenchmarkConcurrentSessions(b *testing.B) {
+	const sessions = 500
+
+	rules := DefaultRules()
+	rules.MaxPlayers = 1
+	rules.UnlimitedMoney = true
+
+	sm := NewSessionsManager(database.NewMemoryStore(), Modes{DefaultMode: rules}, Maps{}, DefaultStreamOptions())
+
+	users := make([]*pb.User, 0, sessions)
+	for i := 0; i < sessions; i++ {
+		session, err := sm.GetSessionForUser(int32(i+1), SessionOptions{})
+		if err != nil {
+			b.Fatalf("create session error: %v", err)
+		}
+		users = append(users, session.Users[0])
+	}
+
+	var next atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		user := users[next.Add(1)%sessions]
+		// the first build succeeds, next ones are rejected as existing
+		// routes, both go through the whole command pipeline
+		from, to := user.License[0], user.License[1]
+		for p.Next() {
+			_ = sm.AddTransport(user.Id, from, to, pb.Transport_BUS)
+		}
+	})
+}
+
+func TestCommandWithoutGameRunner(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  pb.SessionStatus
+		wantErr string
+	}{
+		{name: "waiting", status: pb.SessionStatus_WAITING, wantErr: "session 1 is waiting for players"},
+		// the runner is lost on the server restart
+		{name: "active", status: pb.SessionStatus_ACTIVE, wantErr: "session 1 is active but not running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := database.NewMemoryStore()
+			session := loopSession(0)
+			session.Status = tt.status
+			if err := db.AddSession(session); err != nil {
+				t.Fatalf("add session error: %v", err)
+			}
+			sm := NewSessionsManager(db, Modes{DefaultMode: DefaultRules()}, Maps{}, DefaultStreamOptions())
+
+			err := sm.AddTransport(1, &pb.Coordintates{X: 1, Y: 0}, &pb.Coordintates{X: 2, Y: 0}, pb.Transport_BUS)
+			var gameErr *Error
+			if !errors.As(err, &gameErr) || gameErr.Reason != ReasonSessionNotActive {
+				t.Fatalf("AddTransport error = %v, want %s", err, ReasonSessionNotActive)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("AddTransport error = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
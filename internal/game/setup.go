@@ -1,38 +1,252 @@
 package game
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	pb "game_server/api/v1"
+	"os"
+	"time"
 
-const (
-	maxPlayers         int   = 1
-	sideLen            int32 = 12
-	licenseAreaSideLen int32 = 2
-	startMoney         int32 = 1000
-	TimeLimitMin       int   = 10
-	LicenseCost        int32 = 100
-	passengerFuel      int   = 5
-	OnpPenalty         int32 = 500
+	"github.com/caarlos0/env/v6"
 )
 
-// Transport reward
-const (
-	Reward_BUS   int = 5
-	Reward_METRO int = 10
-	Reward_TAXI  int = 0
-	Reward_TRAM  int = 7
-)
+// Duration is a time.Duration written as "1s", "1m30s" etc. in the rules file and env
+type Duration time.Duration
 
-// Transport travel duration (per unit of distance)
-const (
-	Duration_BUS   time.Duration = time.Second
-	Duration_METRO time.Duration = time.Second
-	Duration_TAXI  time.Duration = time.Second
-	Duration_TRAM  time.Duration = time.Second
-)
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Rules are the game balance settings. Every field can be set in the rules
+// file (json) and overridden by env variable with RULES_ prefix.
+type Rules struct {
+	MaxPlayers         int    `json:"maxPlayers" env:"MAX_PLAYERS"`
+	Width              int32  `json:"width" env:"WIDTH"`   // map size in blocks
+	Height             int32  `json:"height" env:"HEIGHT"` // map size in blocks
+	LicenseAreaSideLen int32  `json:"licenseAreaSideLen" env:"LICENSE_AREA_SIDE_LEN"`
+	StartMoney         int32  `json:"startMoney" env:"START_MONEY"`
+	TimeLimitMin       int    `json:"timeLimitMin" env:"TIME_LIMIT_MIN"`
+	LicenseCost        int32  `json:"licenseCost" env:"LICENSE_COST"`
+	PassengerFuel      int    `json:"passengerFuel" env:"PASSENGER_FUEL"`
+	OnpPenalty         int32  `json:"onpPenalty" env:"ONP_PENALTY"`
+	UnlimitedMoney     bool   `json:"unlimitedMoney" env:"UNLIMITED_MONEY"` // players are never charged
+	BuildPolicy        string `json:"buildPolicy" env:"BUILD_POLICY"`       // which route endpoints must be in the builder's license
+	MapGenerator       string `json:"mapGenerator" env:"MAP_GENERATOR"`     // how maps of new sessions are built
+
+	// Deprecated: use Width and Height. Sets both of them for square maps
+	// of the configs written before non-square maps.
+	SideLen int32 `json:"sideLen,omitempty" env:"SIDE_LEN"`
+
+	// Passengers choose the route with the least travel time plus
+	// TransferPenalty for every change of the line plus FareWeight seconds
+	// for every unit of the fare (the reward of the line owner)
+	TransferPenalty Duration `json:"transferPenalty" env:"TRANSFER_PENALTY"`
+	FareWeight      float64  `json:"fareWeight" env:"FARE_WEIGHT"`
+
+	// Transport reward
+	RewardBus   int `json:"rewardBus" env:"REWARD_BUS"`
+	RewardMetro int `json:"rewardMetro" env:"REWARD_METRO"`
+	RewardTaxi  int `json:"rewardTaxi" env:"REWARD_TAXI"`
+	RewardTram  int `json:"rewardTram" env:"REWARD_TRAM"`
 
-// Transport const
+	// Transport travel duration (per unit of distance)
+	DurationBus   Duration `json:"durationBus" env:"DURATION_BUS"`
+	DurationMetro Duration `json:"durationMetro" env:"DURATION_METRO"`
+	DurationTaxi  Duration `json:"durationTaxi" env:"DURATION_TAXI"`
+	DurationTram  Duration `json:"durationTram" env:"DURATION_TRAM"`
+
+	// Transport cost
+	CostBus   int32 `json:"costBus" env:"COST_BUS"`
+	CostMetro int32 `json:"costMetro" env:"COST_METRO"`
+	CostTaxi  int32 `json:"costTaxi" env:"COST_TAXI"`
+	CostTram  int32 `json:"costTram" env:"COST_TRAM"`
+
+	// Share of the transport cost returned when the route is removed
+	RemoveRefund float64 `json:"removeRefund" env:"REMOVE_REFUND"`
+	// Time an upgraded route is out of service, zero to upgrade at once
+	UpgradeDelay Duration `json:"upgradeDelay" env:"UPGRADE_DELAY"`
+}
+
+// Build policies
 const (
-	Cost_BUS   int32 = 300
-	Cost_METRO int32 = 3000
-	Cost_TAXI  int32 = 1000
-	Cost_TRAM  int32 = 1500
+	BuildBothEndpoints = "both" // the route must lie within the player's license
+	BuildOneEndpoint   = "one"  // the route may lead out of the license
 )
+
+// maxMapBlocks limits the map size, the whole map is sent to every player
+const maxMapBlocks = 1000 * 1000
+
+func DefaultRules() *Rules {
+	return &Rules{
+		MaxPlayers:         1,
+		Width:              12,
+		Height:             12,
+		LicenseAreaSideLen: 2,
+		StartMoney:         1000,
+		TimeLimitMin:       10,
+		LicenseCost:        100,
+		PassengerFuel:      5,
+		OnpPenalty:         500,
+		BuildPolicy:        BuildBothEndpoints,
+		MapGenerator:       MapGeneratorCity,
+		TransferPenalty:    Duration(3 * time.Second),
+		FareWeight:         0.2,
+
+		RewardBus:   5,
+		RewardMetro: 10,
+		RewardTaxi:  0,
+		RewardTram:  7,
+
+		DurationBus:   Duration(time.Second),
+		DurationMetro: Duration(time.Second),
+		DurationTaxi:  Duration(time.Second),
+		DurationTram:  Duration(time.Second),
+
+		CostBus:   300,
+		CostMetro: 3000,
+		CostTaxi:  1000,
+		CostTram:  1500,
+
+		RemoveRefund: 0.5,
+	}
+}
+
+// LoadRules reads the default rules overridden by the rules file (if path is
+// not empty) and then by RULES_* env variables
+func LoadRules(path string) (*Rules, error) {
+	rules := DefaultRules()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read rules file error: %w", err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(rules); err != nil {
+			return nil, fmt.Errorf("parse rules file %s error: %w", path, err)
+		}
+		rules.applySideLen()
+	}
+
+	if err := env.Parse(rules, env.Options{Prefix: "RULES_"}); err != nil {
+		return nil, fmt.Errorf("read rules env error: %w", err)
+	}
+	rules.applySideLen()
+
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *Rules) Validate() error {
+	errs := []error{}
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(r.MaxPlayers >= 1, "maxPlayers must be positive, got %d", r.MaxPlayers)
+	check(r.LicenseAreaSideLen >= 1, "licenseAreaSideLen must be positive, got %d", r.LicenseAreaSideLen)
+	check(r.Width >= r.LicenseAreaSideLen && r.Height >= r.LicenseAreaSideLen, "map %dx%d must fit licenseAreaSideLen %d", r.Width, r.Height, r.LicenseAreaSideLen)
+	check(int64(r.Width)*int64(r.Height) <= maxMapBlocks, "map %dx%d is larger than %d blocks", r.Width, r.Height, maxMapBlocks)
+	_, ok := mapGenerators[r.MapGenerator]
+	check(ok, "mapGenerator must be %q or %q, got %q", MapGeneratorUniform, MapGeneratorCity, r.MapGenerator)
+	if len(errs) == 0 {
+		check(!r.startAreasOverlap(), "start areas of %d players overlap on %dx%d map", r.MaxPlayers, r.Width, r.Height)
+	}
+	check(r.StartMoney >= 0, "startMoney must not be negative, got %d", r.StartMoney)
+	check(r.TimeLimitMin >= 1, "timeLimitMin must be positive, got %d", r.TimeLimitMin)
+	check(r.LicenseCost >= 0, "licenseCost must not be negative, got %d", r.LicenseCost)
+	check(r.PassengerFuel >= 1, "passengerFuel must be positive, got %d", r.PassengerFuel)
+	check(r.TransferPenalty >= 0, "transferPenalty must not be negative, got %s", time.Duration(r.TransferPenalty))
+	check(r.FareWeight >= 0, "fareWeight must not be negative, got %g", r.FareWeight)
+	check(r.OnpPenalty >= 0, "onpPenalty must not be negative, got %d", r.OnpPenalty)
+	check(r.BuildPolicy == BuildBothEndpoints || r.BuildPolicy == BuildOneEndpoint, "buildPolicy must be %q or %q, got %q", BuildBothEndpoints, BuildOneEndpoint, r.BuildPolicy)
+
+	check(r.RewardBus >= 0, "rewardBus must not be negative, got %d", r.RewardBus)
+	check(r.RewardMetro >= 0, "rewardMetro must not be negative, got %d", r.RewardMetro)
+	check(r.RewardTaxi >= 0, "rewardTaxi must not be negative, got %d", r.RewardTaxi)
+	check(r.RewardTram >= 0, "rewardTram must not be negative, got %d", r.RewardTram)
+
+	check(r.DurationBus > 0, "durationBus must be positive, got %s", time.Duration(r.DurationBus))
+	check(r.DurationMetro > 0, "durationMetro must be positive, got %s", time.Duration(r.DurationMetro))
+	check(r.DurationTaxi > 0, "durationTaxi must be positive, got %s", time.Duration(r.DurationTaxi))
+	check(r.DurationTram > 0, "durationTram must be positive, got %s", time.Duration(r.DurationTram))
+
+	check(r.CostBus >= 0, "costBus must not be negative, got %d", r.CostBus)
+	check(r.CostMetro >= 0, "costMetro must not be negative, got %d", r.CostMetro)
+	check(r.CostTaxi >= 0, "costTaxi must not be negative, got %d", r.CostTaxi)
+	check(r.CostTram >= 0, "costTram must not be negative, got %d", r.CostTram)
+
+	check(r.RemoveRefund >= 0 && r.RemoveRefund <= 1, "removeRefund must be from 0 to 1, got %g", r.RemoveRefund)
+	check(r.UpgradeDelay >= 0, "upgradeDelay must not be negative, got %s", time.Duration(r.UpgradeDelay))
+
+	return errors.Join(errs...)
+}
+
+// applySideLen turns the deprecated SideLen into Width and Height. SideLen is
+// reset, so the rules derived from these ones don't override their own size.
+func (r *Rules) applySideLen() {
+	if r.SideLen != 0 {
+		r.Width, r.Height = r.SideLen, r.SideLen
+		r.SideLen = 0
+	}
+}
+
+// startAreasOverlap checks whether the license areas of the players share blocks
+func (r *Rules) startAreasOverlap() bool {
+	areas := generatedStartAreas(r)
+	for i := range areas {
+		for j := 0; j < i; j++ {
+			if areas[i].overlaps(areas[j]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *Rules) TimeLimit() time.Duration {
+	return time.Duration(r.TimeLimitMin) * time.Minute
+}
+
+// checkFunds checks the user can pay if the rules demand payments
+func (r *Rules) checkFunds(user *pb.User, money int32) error {
+	if r.UnlimitedMoney || user.Money >= money {
+		return nil
+	}
+
+	return newError(KindResourceExhausted, ReasonNotEnoughMoney, "", "user %d has %d money, %d needed", user.Id, user.Money, money)
+}
+
+// refund returns money to the user if the rules demand payments
+func (r *Rules) refund(user *pb.User, money int32) {
+	if !r.UnlimitedMoney {
+		user.Money += money
+	}
+}
+
+// charge takes money from the user if the rules demand payments, funds must be checked before
+func (r *Rules) charge(user *pb.User, money int32) {
+	if !r.UnlimitedMoney {
+		user.Money -= money
+	}
+}
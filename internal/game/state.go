@@ -1,193 +1,375 @@
 package game
 
 import (
-	"container/heap"
+	"cmp"
 	"context"
 	pb "game_server/api/v1"
 	"game_server/internal/database"
 	"log"
 	"math/rand"
-	"reflect"
-	"sync"
+	"slices"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	tickInterval = time.Second
+	// the live session is written to the database every persistEveryTicks
+	// ticks and after every player command
+	persistEveryTicks = 5
+)
+
+// GameRunner owns the live session state. The state is changed only on the
+// game loop goroutine: player commands are sent to it through the requests channel,
+// so commands of different sessions never wait for each other.
 type GameRunner struct {
-	sessionId        int32
-	db               *database.DbConnector
-	ctx              context.Context
-	ctxCancel        context.CancelFunc
-	connections      []pb.Api_StateStreamServer
-	network          TransportNetwork
-	networkMutex     sync.Mutex
-	rewardQueue      *RewardQueue
-	onps             []*pb.OutNetworkPassenger
-	lastSessionState *pb.Session
-	moneyMutex       *sync.Mutex
+	sessionId     int32
+	userIds       []int32 // the players, they never change during the game
+	db            database.SessionStore
+	rules         *Rules
+	streamOptions StreamOptions
+	tick          time.Duration // the interval between the states
+	ctx           context.Context
+	ctxCancel     context.CancelFunc
+	connections   subscribers
+	session       *pb.Session
+	requests      chan func()
+	persistQueue  chan *pb.Session
+	persisted     chan struct{}
+	network       TransportNetwork
+	rand          *rand.Rand // every random choice of the game, derived from the session seed
+	rewardQueue   *RewardQueue
+	onps          []*pb.OutNetworkPassenger
+	travellers    *travellers
+	blockChanges  *BlockChanges
+	seq           int64 // seq of the last computed state
+	history       *stateHistory
 }
 
-func NewGameRunner(sessionId int32, db *database.DbConnector, initSessionState *pb.Session, moneyMutex *sync.Mutex) *GameRunner {
+func NewGameRunner(db database.SessionStore, rules *Rules, streamOptions StreamOptions, session *pb.Session) *GameRunner {
 	ctx, cxtCancel := context.WithCancel(context.Background())
 
-	rewatdQueue := &RewardQueue{}
-	heap.Init(rewatdQueue)
+	userIds := make([]int32, 0, len(session.Users))
+	for _, user := range session.Users {
+		userIds = append(userIds, user.Id)
+	}
 
 	return &GameRunner{
-		sessionId:        sessionId,
-		ctx:              ctx,
-		db:               db,
-		ctxCancel:        cxtCancel,
-		connections:      []pb.Api_StateStreamServer{},
-		network:          TransportNetwork{},
-		rewardQueue:      rewatdQueue,
-		onps:             []*pb.OutNetworkPassenger{},
-		lastSessionState: initSessionState,
-		moneyMutex:       moneyMutex,
+		sessionId:     session.Id,
+		userIds:       userIds,
+		ctx:           ctx,
+		db:            db,
+		rules:         rules,
+		streamOptions: streamOptions,
+		tick:          tickInterval,
+		ctxCancel:     cxtCancel,
+		session:       session,
+		requests:      make(chan func()),
+		persistQueue:  make(chan *pb.Session, 1),
+		persisted:     make(chan struct{}),
+		network:       *networkOf(session.Map),
+		rand:          sessionRand(session.Seed, randGame),
+		rewardQueue:   NewRewardQueue(),
+		onps:          []*pb.OutNetworkPassenger{},
+		travellers:    newTravellers(),
+		blockChanges:  NewBlockChanges(),
+		history:       newStateHistory(streamOptions.HistoryLen),
+	}
+}
+
+// subscribe streams the states to srv until the client leaves or the game ends.
+// The stream starts with a snapshot, a client which has seen states up to
+// lastSeq gets the missed ones instead if they are kept.
+// It blocks until the stream is closed and returns the error which closed it.
+func (gr *GameRunner) subscribe(srv pb.Api_StateStreamServer, lastSeq int64) error {
+	sub := newSubscriber(srv, gr.streamOptions.BufferSize)
+
+	// the subscriber is added on the loop, so no state is lost or sent twice
+	// between the missed states and the published ones
+	err := gr.do(func() {
+		sub.backlog = gr.missedStates(lastSeq)
+		gr.connections.add(sub)
+	})
+	if err != nil {
+		return err
 	}
+	defer gr.connections.remove(sub)
+
+	sub.run(gr.ctx)
+	if sub.err != nil {
+		log.Printf("session %d state stream closed: %v\n", gr.sessionId, sub.err)
+	}
+
+	return sub.err
 }
 
-func (gr *GameRunner) addConnection(srv pb.Api_StateStreamServer) context.Context {
-	gr.connections = append(gr.connections, srv)
-	return gr.ctx
+// do runs f on the game loop goroutine and waits for it to finish
+func (gr *GameRunner) do(f func()) error {
+	done := make(chan struct{})
+
+	select {
+	case gr.requests <- func() {
+		f()
+		close(done)
+	}:
+	case <-gr.ctx.Done():
+		return newError(KindFailedPrecondition, ReasonSessionNotActive, "", "session %d is finished", gr.sessionId)
+	}
+
+	<-done
+	return nil
+}
+
+// execute validates and applies the player command to the live session
+func (gr *GameRunner) execute(cmd command) error {
+	var err error
+	doErr := gr.do(func() {
+		state := &commandState{
+			session:    gr.session,
+			gameMap:    SessionMap(gr.session),
+			rules:      gr.rules,
+			network:    &gr.network,
+			changes:    gr.blockChanges,
+			travellers: gr.travellers,
+			rewards:    gr.rewardQueue,
+		}
+
+		if err = cmd.validate(state); err != nil {
+			return
+		}
+
+		cmd.apply(state)
+		gr.persist()
+	})
+	if doErr != nil {
+		return doErr
+	}
+
+	return err
+}
+
+// missedStates returns the states after lastSeq or a snapshot if they are not
+// kept anymore. Zero lastSeq means the client has nothing yet.
+func (gr *GameRunner) missedStates(lastSeq int64) []*pb.State {
+	if lastSeq > 0 {
+		if states, ok := gr.history.since(lastSeq); ok {
+			return states
+		}
+		log.Printf("session %d states after %d are not kept, sending snapshot %d\n", gr.sessionId, lastSeq, gr.seq)
+	}
+
+	return []*pb.State{gr.snapshotState()}
+}
+
+// snapshotState returns the whole live session as a state
+func (gr *GameRunner) snapshotState() *pb.State {
+	users := make([]*pb.User, 0, len(gr.session.Users))
+	for _, user := range gr.session.Users {
+		users = append(users, proto.Clone(user).(*pb.User))
+	}
+
+	blocks := make([]*pb.Block, 0, len(gr.session.Map))
+	for _, block := range gr.session.Map {
+		blocks = append(blocks, proto.Clone(block).(*pb.Block))
+	}
+
+	onps := make([]*pb.OutNetworkPassenger, 0, len(gr.onps))
+	for _, onp := range gr.onps {
+		onps = append(onps, proto.Clone(onp).(*pb.OutNetworkPassenger))
+	}
+
+	// tracks are never changed after they are sent
+	now := time.Now()
+	tracks, passengers := gr.travellers.snapshot(now)
+
+	return &pb.State{
+		Seq:                  gr.seq,
+		Kind:                 pb.StateKind_SNAPSHOT,
+		TimeLeft:             durationpb.New(gr.timeLeft(now)),
+		Users:                users,
+		ChangedBlocks:        blocks,
+		NewEvents:            []*pb.Event{},
+		Tracks:               tracks,
+		OutNetworkPassengers: onps,
+		Passengers:           passengers,
+		PendingRewards:       gr.pendingRewards(),
+	}
+}
+
+// snapshot returns a copy of the live session
+func (gr *GameRunner) snapshot() (*pb.Session, error) {
+	var session *pb.Session
+	err := gr.do(func() {
+		session = proto.Clone(gr.session).(*pb.Session)
+	})
+
+	return session, err
+}
+
+// persist queues a copy of the live session to be written to the database.
+// Only the latest queued copy is written if the database falls behind.
+func (gr *GameRunner) persist() {
+	select {
+	case <-gr.persistQueue:
+	default:
+	}
+
+	gr.persistQueue <- proto.Clone(gr.session).(*pb.Session)
+}
+
+func (gr *GameRunner) persistLoop() {
+	for session := range gr.persistQueue {
+		if err := gr.db.UpdateSession(session); err != nil {
+			log.Printf("game loop for session %d, update session in db error: %v", gr.sessionId, err)
+		}
+	}
+
+	close(gr.persisted)
 }
 
 // kF computes value for k (+ jitter) based on game progression
-func kF(alpha float64) int {
+func kF(rng *rand.Rand, alpha float64) int {
 	if alpha < 0 {
 		return 0 // retry next time hon
 	}
 	// Idea: approx every 20 ticks in the beginning, every 5 ticks at the end
-	return 16 - int(alpha*15) + rand.Intn(4)
+	return 16 - int(alpha*15) + rng.Intn(4)
 }
 
 // nF computes value for n (+ jitter) based on game progression
-func nF(alpha float64) int {
+func nF(rng *rand.Rand, alpha float64) int {
 	if alpha < 0 {
 		return 0 // woah it aint time yet
 	}
 	// Idea: at most 1 at the beginning, at most 25 at the end
 	// Linearly adjust n
-	return 1 + rand.Intn(1+int(alpha*25))
+	return 1 + rng.Intn(1+int(alpha*25))
 }
 
 func (gr *GameRunner) startGameComputation() {
+	go gr.persistLoop()
+
 	go func() {
 		// k is a counter managing generation of travellers. Each time it
 		// reaches zero, a handful of travellers is produced
 		k := 1
+		ticks := 0
+
+		ticker := time.NewTicker(gr.tick)
+		defer ticker.Stop()
 
-		var session *pb.Session
-		var err error
+		session := gr.session
 		for {
-			gr.moneyMutex.Lock()
-			k--
-			session, err = gr.db.GetSession(gr.sessionId)
-			if err != nil {
-				log.Printf("game loop for session %d, get session from db error: %v\n", gr.sessionId, err)
-				gr.ctxCancel()
-				gr.moneyMutex.Unlock()
-				break
+			select {
+			case request := <-gr.requests:
+				request()
+				continue
+			case <-ticker.C:
 			}
 
-			if session.StartTime.AsTime().Add(time.Duration(TimeLimitMin) * time.Minute).Before(time.Now()) {
+			k--
+			ticks++
+
+			if session.StartTime.AsTime().Add(gr.rules.TimeLimit()).Before(time.Now()) {
 				log.Printf("time is up, finishing session\n")
-				gr.moneyMutex.Unlock()
 				break
 			}
 
+			// kF gives 0 until the session starts, k goes below zero then
 			to_spawn := 0
-			if k == 0 {
-				alpha := time.Now().Sub(session.StartTime.AsTime()).Minutes() / float64(TimeLimitMin)
+			if k <= 0 {
+				alpha := gr.progress(time.Now())
 
 				// set the counter to new value
-				k = kF(alpha)
+				k = kF(gr.rand, alpha)
 				// select the number of travellers spawned
-				to_spawn = nF(alpha)
+				to_spawn = nF(gr.rand, alpha)
 			}
 
 			state, err := gr.computeState(session, to_spawn)
 			if err != nil {
 				log.Printf("game loop for session %d, compute state error: %v", gr.sessionId, err)
-				session.Status = pb.SessionStatus_FINISHED
-				gr.ctxCancel()
-				gr.moneyMutex.Unlock()
-				break
-			}
-
-			if err := gr.db.UpdateSession(session); err != nil {
-				log.Printf("game loop for session %d, update session in db error: %v", gr.sessionId, err)
-				gr.ctxCancel()
-				gr.moneyMutex.Unlock()
 				break
 			}
 
-			gr.moneyMutex.Unlock()
-
-			for _, srv := range gr.connections {
-				if err := srv.Send(state); err != nil {
-
-					log.Printf("game loop for session %d, send state error: %v", gr.sessionId, err)
-					// gr.ctxCancel()
-				}
+			if ticks%persistEveryTicks == 0 {
+				gr.persist()
 			}
 
-			time.Sleep(time.Second)
+			gr.connections.publish(state, gr.streamOptions.SlowPolicy, gr.snapshotState)
 		}
 
 		session.Status = pb.SessionStatus_FINISHED
 		log.Printf("session finished\n")
-		if err := gr.db.UpdateSession(session); err != nil {
-			log.Printf("game end for session %d, update session in db error: %v", gr.sessionId, err)
-		}
+		gr.persist()
+		close(gr.persistQueue)
+		<-gr.persisted
 
 		gr.ctxCancel()
 	}()
 }
 
-func (gr *GameRunner) extendNetwork(userId int32, p1 *pb.Coordintates, p2 *pb.Coordintates, transport pb.Transport) error {
-	gr.networkMutex.Lock()
-	defer gr.networkMutex.Unlock()
-
-	coords1 := Coords{
-		X: p1.X,
-		Y: p1.Y,
-	}
-	coords2 := Coords{
-		X: p2.X,
-		Y: p2.Y,
+// generateTravellers picks n trips of the current day phase and routes them
+// through the network, the trips the network can't serve are dropped
+func (gr *GameRunner) generateTravellers(now time.Time, n int) []Path {
+	paths := []Path{}
+	if n == 0 {
+		return paths
 	}
 
-	return gr.network.ConnectBlocks(userId, coords1, coords2, transport)
-}
+	model := newDemandModel(SessionMap(gr.session), &gr.network)
+	phase := phaseOf(gr.progress(now))
 
-func (gr *GameRunner) generateTravellers(n int) []Path {
-	gr.networkMutex.Lock()
-	defer gr.networkMutex.Unlock()
+	for i := 0; i < n; i++ {
+		trip, ok := model.trip(gr.rand, phase)
+		if !ok {
+			continue
+		}
+		if path, ok := gr.network.BestPath(gr.rules, now, trip.from, trip.to); ok {
+			paths = append(paths, path)
+		}
+	}
 
-	// Let's filter out the starting edges that have
-	starts := []Coords{}
+	return paths
+}
 
+// sendToRoad routes the passengers who have got a station in their block
+func (gr *GameRunner) sendToRoad(now time.Time, onps []*pb.OutNetworkPassenger) []Path {
 	paths := []Path{}
-
-	for s := range gr.network.blocks {
-		starts = append(starts, s)
+	if len(onps) == 0 {
+		return paths
 	}
 
-	if len(starts) > 0 {
-		for i := 0; i < n; i++ {
-			start := starts[rand.Intn(len(starts))]
-			path := gr.network.RandomPath(start, passengerFuel)
-			if len(path.Hops) > 0 {
-				paths = append(paths, path)
-			}
+	gameMap := SessionMap(gr.session)
+	model := newDemandModel(gameMap, &gr.network)
+	phase := phaseOf(gr.progress(now))
+
+	for _, onp := range onps {
+		block, ok := gameMap.Block(onp.Position.X, onp.Position.Y)
+		if !ok {
+			continue
+		}
+
+		trip, ok := model.tripFromBlock(gr.rand, phase, coordsOf(onp.Position), block.Type)
+		if !ok {
+			continue
+		}
+		if path, ok := gr.network.BestPath(gr.rules, now, trip.from, trip.to); ok {
+			paths = append(paths, path)
 		}
 	}
 
 	return paths
 }
 
+// progress returns the share of the session time passed, from 0 at the start to 1 at the end
+func (gr *GameRunner) progress(now time.Time) float64 {
+	return now.Sub(gr.session.StartTime.AsTime()).Seconds() / gr.rules.TimeLimit().Seconds()
+}
+
 // ONP means OutNetworkPassenger
 func (gr *GameRunner) generateONP(session *pb.Session) []*pb.OutNetworkPassenger {
 	nowTime := time.Now()
@@ -203,10 +385,10 @@ func (gr *GameRunner) generateONP(session *pb.Session) []*pb.OutNetworkPassenger
 
 	newOnps := []*pb.OutNetworkPassenger{}
 
-	passengersNum := 3 + rand.Int31n(1)
+	passengersNum := min(3+gr.rand.Intn(1), len(points))
 
-	for i := range rand.Perm(len(points))[:passengersNum] {
-		ttl := time.Duration(30+rand.Int31n(60)) * time.Second
+	for _, i := range gr.rand.Perm(len(points))[:passengersNum] {
+		ttl := time.Duration(30+gr.rand.Int31n(60)) * time.Second
 
 		onp := &pb.OutNetworkPassenger{
 			Position:   points[i],
@@ -220,21 +402,24 @@ func (gr *GameRunner) generateONP(session *pb.Session) []*pb.OutNetworkPassenger
 	return newOnps
 }
 
+// onpsBurnOrGetSendToRoad returns the passengers who have got a station in
+// their block and fines the license owners of the ones waited too long. Both
+// are forgotten, the others keep waiting.
 func (gr *GameRunner) onpsBurnOrGetSendToRoad(session *pb.Session) []*pb.OutNetworkPassenger {
 	currentTime := time.Now()
 	sendToRoad := []*pb.OutNetworkPassenger{}
 
-	for i, onp := range gr.onps {
+	kept := gr.onps[:0]
+	for _, onp := range gr.onps {
 		if _, ok := gr.network.blocks[Coords{X: onp.Position.X, Y: onp.Position.Y}]; ok {
 			sendToRoad = append(sendToRoad, onp)
-			gr.onps = append(gr.onps[:i], gr.onps[i+1:]...)
 			continue
 		}
 		if onp.TimeToBurn.AsTime().Before(currentTime) {
 			for _, user := range session.Users {
 				for _, block := range user.License {
 					if onp.Position.X == block.X && onp.Position.Y == block.Y {
-						money := user.Money - OnpPenalty
+						money := user.Money - gr.rules.OnpPenalty
 						if money < 0 {
 							money = 0
 						}
@@ -244,10 +429,12 @@ func (gr *GameRunner) onpsBurnOrGetSendToRoad(session *pb.Session) []*pb.OutNetw
 					}
 				}
 			}
-
-			gr.onps = append(gr.onps[:i], gr.onps[i+1:]...)
+			continue
 		}
+		kept = append(kept, onp)
 	}
+	clear(gr.onps[len(kept):])
+	gr.onps = kept
 
 	return sendToRoad
 }
@@ -256,81 +443,80 @@ func (gr *GameRunner) computeState(session *pb.Session, to_spawn int) (*pb.State
 	gr.rewardsAccrual(session)
 	sendToRoadOnps := gr.onpsBurnOrGetSendToRoad(session)
 
-	changedBlocks := []*pb.Block{}
-	for i := range session.Map {
-		if !reflect.DeepEqual(session.Map[i], gr.lastSessionState.Map[i]) {
-			changedBlocks = append(changedBlocks, session.Map[i])
-		}
-	}
+	changedBlocks := gr.blockChanges.Take(session.Map)
 
 	now := time.Now()
-	paths := gr.generateTravellers(to_spawn)
-	for _, onp := range sendToRoadOnps {
-		paths = append(paths, gr.network.RandomPath(Coords{X: onp.Position.X, Y: onp.Position.Y}, passengerFuel))
-	}
+	paths := gr.generateTravellers(now, to_spawn)
+	paths = append(paths, gr.sendToRoad(now, sendToRoadOnps)...)
 
 	newOnps := []*pb.OutNetworkPassenger{}
 	if to_spawn > 0 && session.StartTime.AsTime().Before(now) {
 		newOnps = gr.generateONP(session)
 	}
 
-	// No we shall reward generously the completers of the path
+	// the travellers set off now, their carriers are paid as they pass the hops
+	tracks := gr.travellers.takeCut()
 	for _, path := range paths {
-		rewards := path.Reward()
-
-		for user, money := range rewards {
-			heap.Push(gr.rewardQueue, &Reward{
-				userId:         user,
-				money:          int32(money),
-				activationTime: now,
-			})
-		}
+		t := gr.travellers.setOff(gr.rules, gr.rewardQueue, path, now)
+		tracks = append(tracks, t.track)
 	}
+	passengers := gr.travellers.move(now)
 
-	// And we shall send the generated paths to the client
-	tracks := []*pb.Path{}
-	for _, path := range paths {
-		coords := []*pb.Coordintates{{
-			X: path.Start.X,
-			Y: path.Start.Y,
-		}}
-
-		for _, hop := range path.Hops {
-			coords = append(coords, &pb.Coordintates{
-				X: hop.To.X,
-				Y: hop.To.Y,
-			})
-		}
-
-		tracks = append(tracks, &pb.Path{
-			Points: coords,
-		})
+	// the state is sent by subscriber goroutines while the loop changes the
+	// session, so it must not share messages with it
+	users := make([]*pb.User, 0, len(session.Users))
+	for _, user := range session.Users {
+		users = append(users, proto.Clone(user).(*pb.User))
 	}
 
+	gr.seq++
 	state := &pb.State{
-		Users:                session.Users,
+		Seq:                  gr.seq,
+		Kind:                 pb.StateKind_DELTA,
+		TimeLeft:             durationpb.New(gr.timeLeft(now)),
+		Users:                users,
 		NewEvents:            []*pb.Event{},
 		ChangedBlocks:        changedBlocks,
 		Tracks:               tracks,
 		OutNetworkPassengers: newOnps,
+		Passengers:           passengers,
+		PendingRewards:       gr.pendingRewards(),
 	}
 
-	gr.lastSessionState = session
+	gr.history.push(state)
 
 	return state, nil
 }
 
+// timeLeft returns the time until the session finishes
+func (gr *GameRunner) timeLeft(now time.Time) time.Duration {
+	left := gr.session.StartTime.AsTime().Add(gr.rules.TimeLimit()).Sub(now)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// rewardsAccrual pays the rewards which are due
 func (gr *GameRunner) rewardsAccrual(session *pb.Session) {
-	currentTime := time.Now()
+	for _, reward := range gr.rewardQueue.PopDue(time.Now()) {
+		if user := findUser(session, reward.userId); user != nil {
+			user.Money += reward.money
+		}
+	}
+}
 
-	for gr.rewardQueue.Len() > 0 && gr.rewardQueue.Top().activationTime.After(currentTime) {
-		reward := heap.Pop(gr.rewardQueue).(*Reward)
+// pendingRewards returns the money the users are still to get, ordered by user id
+func (gr *GameRunner) pendingRewards() []*pb.PendingReward {
+	pending := gr.rewardQueue.Pending()
 
-		for _, user := range session.Users {
-			if user.Id == reward.userId {
-				user.Money += reward.money
-				break
-			}
-		}
+	rewards := make([]*pb.PendingReward, 0, len(pending))
+	for userId, money := range pending {
+		rewards = append(rewards, &pb.PendingReward{UserId: userId, Money: money})
 	}
+	slices.SortFunc(rewards, func(a, b *pb.PendingReward) int {
+		return cmp.Compare(a.UserId, b.UserId)
+	})
+
+	return rewards
 }
@@ -0,0 +1,53 @@
+package game
+
+import pb "game_server/api/v1"
+
+// stateHistory is a ring buffer of the last published delta states, used to
+// resume the state streams of reconnected clients
+type stateHistory struct {
+	states []*pb.State
+	next   int // index the next state is written to
+	size   int
+}
+
+func newStateHistory(capacity int) *stateHistory {
+	return &stateHistory{
+		states: make([]*pb.State, capacity),
+	}
+}
+
+func (h *stateHistory) push(state *pb.State) {
+	if len(h.states) == 0 {
+		return
+	}
+
+	h.states[h.next] = state
+	h.next = (h.next + 1) % len(h.states)
+	if h.size < len(h.states) {
+		h.size++
+	}
+}
+
+// since returns the states following lastSeq up to the newest one, false if
+// some of them are not kept anymore or lastSeq is unknown
+func (h *stateHistory) since(lastSeq int64) ([]*pb.State, bool) {
+	if h.size == 0 {
+		return nil, false
+	}
+
+	first := (h.next - h.size + len(h.states)) % len(h.states)
+	newest := h.states[(h.next-1+len(h.states))%len(h.states)]
+	if lastSeq > newest.Seq || lastSeq < h.states[first].Seq-1 {
+		return nil, false
+	}
+
+	states := []*pb.State{}
+	for i := 0; i < h.size; i++ {
+		state := h.states[(first+i)%len(h.states)]
+		if state.Seq > lastSeq {
+			states = append(states, state)
+		}
+	}
+
+	return states, true
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"game_server/internal/database"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// loopSession returns a session starting after the delay on a 3x1 map with a
+// bus route between the residential block and the industrial one
+func loopSession(delay time.Duration) *pb.Session {
+	blocks := []*pb.Block{
+		{Position: &pb.Coordintates{X: 0, Y: 0}, Type: pb.BlockType_RESIDENTIAL, Capacity: 10},
+		{Position: &pb.Coordintates{X: 1, Y: 0}, Type: pb.BlockType_INDUSTRIAL, Capacity: 10},
+		{Position: &pb.Coordintates{X: 2, Y: 0}, Type: pb.BlockType_RESIDENTIAL, Capacity: 10},
+	}
+	blocks[0].Connectors = []*pb.Connector{{UserId: 1, Destination: blocks[1].Position, Transport: pb.Transport_BUS}}
+	blocks[1].Connectors = []*pb.Connector{{UserId: 1, Destination: blocks[0].Position, Transport: pb.Transport_BUS}}
+
+	return &pb.Session{
+		Id:        1,
+		Width:     3,
+		Height:    1,
+		Seed:      1,
+		Status:    pb.SessionStatus_ACTIVE,
+		StartTime: timestamppb.New(time.Now().Add(delay)),
+		Map:       blocks,
+		Users: []*pb.User{{
+			Id:      1,
+			License: []*pb.Coordintates{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}},
+		}},
+	}
+}
+
+func TestGameLoopSpawnsTravellers(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	db := database.NewMemoryStore()
+	session := loopSession(delay)
+	gr := NewGameRunner(db, DefaultRules(), DefaultStreamOptions(), session)
+	gr.tick = 5 * time.Millisecond
+	gr.startGameComputation()
+
+	var travellers int64
+	var onps int
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && (travellers == 0 || onps == 0) {
+		err := gr.do(func() {
+			travellers = gr.travellers.lastId
+			onps = len(gr.onps)
+			if time.Now().Before(gr.session.StartTime.AsTime()) && (travellers > 0 || onps > 0) {
+				t.Errorf("%d travellers and %d passengers out of the network before the start", travellers, onps)
+			}
+		})
+		if err != nil {
+			t.Fatalf("game loop error: %v", err)
+		}
+		time.Sleep(gr.tick)
+	}
+
+	if travellers == 0 {
+		t.Errorf("no travellers are spawned after the start")
+	}
+	if onps == 0 {
+		t.Errorf("no passengers out of the network are spawned after the start")
+	}
+
+	// the time is up on the next tick
+	_ = gr.do(func() {
+		gr.session.StartTime = timestamppb.New(time.Now().Add(-gr.rules.TimeLimit()))
+	})
+	<-gr.ctx.Done()
+}
+
+func TestOnpsBurnOrGetSendToRoad(t *testing.T) {
+	// (0, 0) and (1, 0) are served by the network, (2, 0) is not
+	tests := []struct {
+		name          string
+		onps          []int32 // x of the passengers, negative ones have burnt
+		wantSent      []int32
+		wantKept      []int32
+		wantMoneyLeft int32
+	}{
+		{name: "none", wantMoneyLeft: 1000},
+		{name: "waiting", onps: []int32{2, 2}, wantKept: []int32{2, 2}, wantMoneyLeft: 1000},
+		{name: "all sent", onps: []int32{0, 1, 0}, wantSent: []int32{0, 1, 0}, wantMoneyLeft: 1000},
+		{name: "all burnt", onps: []int32{-2, -2, -2}, wantMoneyLeft: 0},
+		{name: "mixed", onps: []int32{0, -2, 2, 1, -2, 2, 0}, wantSent: []int32{0, 1, 0}, wantKept: []int32{2, 2}, wantMoneyLeft: 0},
+		{name: "one burnt", onps: []int32{2, -2, 2}, wantKept: []int32{2, 2}, wantMoneyLeft: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := loopSession(0)
+			session.Users[0].Money = 1000
+			gr := NewGameRunner(database.NewMemoryStore(), DefaultRules(), DefaultStreamOptions(), session)
+
+			now := time.Now()
+			for _, x := range tt.onps {
+				burn := now.Add(time.Minute)
+				if x < 0 {
+					x, burn = -x, now.Add(-time.Second)
+				}
+				gr.onps = append(gr.onps, &pb.OutNetworkPassenger{
+					Position:   &pb.Coordintates{X: x, Y: 0},
+					TimeToBurn: timestamppb.New(burn),
+				})
+			}
+
+			sent := gr.onpsBurnOrGetSendToRoad(session)
+
+			checkOnps(t, "sent", sent, tt.wantSent)
+			checkOnps(t, "kept", gr.onps, tt.wantKept)
+			if money := session.Users[0].Money; money != tt.wantMoneyLeft {
+				t.Errorf("money left %d, want %d", money, tt.wantMoneyLeft)
+			}
+		})
+	}
+}
+
+func checkOnps(t *testing.T, name string, onps []*pb.OutNetworkPassenger, wantX []int32) {
+	t.Helper()
+
+	if len(onps) != len(wantX) {
+		t.Fatalf("%d passengers are %s, want %d", len(onps), name, len(wantX))
+	}
+	for i, onp := range onps {
+		if onp.Position.X != wantX[i] {
+			t.Errorf("%s passenger %d is at x %d, want %d", name, i, onp.Position.X, wantX[i])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	pb "game_server/api/v1"
+	"sync"
+)
+
+// Policies for subscribers which don't read states as fast as they are produced
+const (
+	SlowSubscriberDrop       = "drop"       // the queued states are replaced by a snapshot
+	SlowSubscriberDisconnect = "disconnect" // the stream is closed
+)
+
+// StreamOptions configure state streaming to clients
+type StreamOptions struct {
+	BufferSize int    // states queued per subscriber
+	SlowPolicy string // SlowSubscriberDrop or SlowSubscriberDisconnect
+	HistoryLen int    // delta states kept to resume streams, older clients get a snapshot
+}
+
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		BufferSize: 16,
+		SlowPolicy: SlowSubscriberDisconnect,
+		HistoryLen: 60,
+	}
+}
+
+func (o StreamOptions) Validate() error {
+	if o.BufferSize < 1 {
+		return fmt.Errorf("stream buffer size must be positive, got %d", o.BufferSize)
+	}
+	if o.SlowPolicy != SlowSubscriberDrop && o.SlowPolicy != SlowSubscriberDisconnect {
+		return fmt.Errorf("slow subscriber policy must be %q or %q, got %q", SlowSubscriberDrop, SlowSubscriberDisconnect, o.SlowPolicy)
+	}
+	if o.HistoryLen < 0 {
+		return fmt.Errorf("stream history length must not be negative, got %d", o.HistoryLen)
+	}
+	return nil
+}
+
+// subscriber sends states to one client stream on the goroutine of its rpc,
+// so a slow client never holds the game loop
+type subscriber struct {
+	srv      pb.Api_StateStreamServer
+	backlog  []*pb.State // sent before the queued states
+	states   chan *pb.State
+	kicked   chan struct{}
+	kickOnce sync.Once
+	err      error // why the stream was closed, valid after run returns
+}
+
+func newSubscriber(srv pb.Api_StateStreamServer, bufferSize int) *subscriber {
+	return &subscriber{
+		srv:    srv,
+		states: make(chan *pb.State, bufferSize),
+		kicked: make(chan struct{}),
+	}
+}
+
+// run sends queued states until the client leaves, the game ends or the
+// subscriber is kicked for being slow
+func (s *subscriber) run(gameCtx context.Context) {
+	for _, state := range s.backlog {
+		if err := s.srv.Send(state); err != nil {
+			s.err = err
+			return
+		}
+	}
+	s.backlog = nil
+
+	for {
+		select {
+		case state := <-s.states:
+			if err := s.srv.Send(state); err != nil {
+				s.err = err
+				return
+			}
+		case <-s.kicked:
+			s.err = newError(KindResourceExhausted, ReasonSlowSubscriber, "", "state stream is read too slowly")
+			return
+		case <-s.srv.Context().Done():
+			return
+		case <-gameCtx.Done():
+			return
+		}
+	}
+}
+
+// offer queues the state without blocking, applying the policy if the queue
+// is full. A delta can't be skipped, so the dropped states are replaced by the
+// snapshot which includes the state.
+func (s *subscriber) offer(state *pb.State, policy string, snapshot func() *pb.State) {
+	select {
+	case s.states <- state:
+		return
+	default:
+	}
+
+	if policy == SlowSubscriberDisconnect {
+		s.kickOnce.Do(func() { close(s.kicked) })
+		return
+	}
+
+	for drained := false; !drained; {
+		select {
+		case <-s.states:
+		default:
+			drained = true
+		}
+	}
+	// only publish adds states, so the queue has room
+	s.states <- snapshot()
+}
+
+// subscribers are the state streams of a game safe for concurrent use
+type subscribers struct {
+	mutex   sync.RWMutex
+	streams map[*subscriber]struct{}
+}
+
+func (s *subscribers) add(sub *subscriber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.streams == nil {
+		s.streams = map[*subscriber]struct{}{}
+	}
+	s.streams[sub] = struct{}{}
+}
+
+func (s *subscribers) remove(sub *subscriber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.streams, sub)
+}
+
+// publish offers the state to every subscriber. snapshot returns the whole
+// game as of the state, it is called once at most for the slow subscribers.
+func (s *subscribers) publish(state *pb.State, policy string, snapshot func() *pb.State) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var snap *pb.State
+	once := func() *pb.State {
+		if snap == nil {
+			snap = snapshot()
+		}
+		return snap
+	}
+
+	for sub := range s.streams {
+		sub.offer(state, policy, once)
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"testing"
+)
+
+func TestSubscriberOfferFullQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		offered    int
+		wantQueued []pb.StateKind
+		wantKicked bool
+	}{
+		{name: "drop with room", policy: SlowSubscriberDrop, offered: 2, wantQueued: []pb.StateKind{pb.StateKind_DELTA, pb.StateKind_DELTA}},
+		{name: "drop replaces the queue by a snapshot", policy: SlowSubscriberDrop, offered: 3, wantQueued: []pb.StateKind{pb.StateKind_SNAPSHOT}},
+		{name: "drop after snapshot queues deltas", policy: SlowSubscriberDrop, offered: 4, wantQueued: []pb.StateKind{pb.StateKind_SNAPSHOT, pb.StateKind_DELTA}},
+		{name: "disconnect with room", policy: SlowSubscriberDisconnect, offered: 2, wantQueued: []pb.StateKind{pb.StateKind_DELTA, pb.StateKind_DELTA}},
+		{name: "disconnect kicks", policy: SlowSubscriberDisconnect, offered: 3, wantQueued: []pb.StateKind{pb.StateKind_DELTA, pb.StateKind_DELTA}, wantKicked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := newSubscriber(nil, 2)
+
+			snapshots := 0
+			for seq := int64(1); seq <= int64(tt.offered); seq++ {
+				snapshot := func() *pb.State {
+					snapshots++
+					return &pb.State{Seq: seq, Kind: pb.StateKind_SNAPSHOT}
+				}
+				sub.offer(&pb.State{Seq: seq, Kind: pb.StateKind_DELTA}, tt.policy, snapshot)
+			}
+
+			queued := []pb.StateKind{}
+			lastSeq := int64(0)
+			for len(sub.states) > 0 {
+				state := <-sub.states
+				if state.Seq <= lastSeq {
+					t.Errorf("state %d is queued after %d", state.Seq, lastSeq)
+				}
+				lastSeq = state.Seq
+				queued = append(queued, state.Kind)
+			}
+
+			if len(queued) != len(tt.wantQueued) {
+				t.Fatalf("queued %v, want %v", queued, tt.wantQueued)
+			}
+			for i := range queued {
+				if queued[i] != tt.wantQueued[i] {
+					t.Fatalf("queued %v, want %v", queued, tt.wantQueued)
+				}
+			}
+			if lastSeq != int64(tt.offered) && !tt.wantKicked {
+				t.Errorf("last queued state is %d, want %d", lastSeq, tt.offered)
+			}
+			if snapshots > 1 {
+				t.Errorf("%d snapshots are made, want 1 at most", snapshots)
+			}
+
+			kicked := false
+			select {
+			case <-sub.kicked:
+				kicked = true
+			default:
+			}
+			if kicked != tt.wantKicked {
+				t.Errorf("kicked = %v, want %v", kicked, tt.wantKicked)
+			}
+		})
+	}
+}
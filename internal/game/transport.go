@@ -5,47 +5,57 @@ import (
 	"time"
 )
 
-func transportReward(t pb.Transport) int {
+func (r *Rules) transportReward(t pb.Transport) int {
 	switch t {
 	case pb.Transport_BUS:
-		return Reward_BUS
+		return r.RewardBus
 	case pb.Transport_METRO:
-		return Reward_METRO
+		return r.RewardMetro
 	case pb.Transport_TAXI:
-		return Reward_TAXI
+		return r.RewardTaxi
 	case pb.Transport_TRAM:
-		return Reward_TRAM
+		return r.RewardTram
 	default:
 		return 0
 	}
 }
 
-func transportDuration(t pb.Transport) time.Duration {
+func (r *Rules) transportDuration(t pb.Transport) time.Duration {
 	switch t {
 	case pb.Transport_BUS:
-		return Duration_BUS
+		return time.Duration(r.DurationBus)
 	case pb.Transport_METRO:
-		return Duration_METRO
+		return time.Duration(r.DurationMetro)
 	case pb.Transport_TAXI:
-		return Duration_TAXI
+		return time.Duration(r.DurationTaxi)
 	case pb.Transport_TRAM:
-		return Duration_TRAM
+		return time.Duration(r.DurationTram)
 	default:
 		return 0
 	}
 }
 
-func transportCost(t pb.Transport) int32 {
+func (r *Rules) transportCost(t pb.Transport) int32 {
 	switch t {
 	case pb.Transport_BUS:
-		return Cost_BUS
+		return r.CostBus
 	case pb.Transport_METRO:
-		return Cost_METRO
+		return r.CostMetro
 	case pb.Transport_TAXI:
-		return Cost_TAXI
+		return r.CostTaxi
 	case pb.Transport_TRAM:
-		return Cost_TRAM
+		return r.CostTram
 	default:
 		return 0
 	}
 }
+
+// upgradeCost returns the money the owner pays to change the transport of the route
+func (r *Rules) upgradeCost(from, to pb.Transport) int32 {
+	return r.transportCost(to) - r.transportCost(from)
+}
+
+// removeRefund returns the money the owner gets back for the removed route
+func (r *Rules) removeRefund(t pb.Transport) int32 {
+	return int32(float64(r.transportCost(t)) * r.RemoveRefund)
+}
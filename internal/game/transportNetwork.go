@@ -1,22 +1,35 @@
 package game
 
 import (
-	"fmt"
 	pb "game_server/api/v1"
 	"math"
-	"math/rand"
 	"time"
 )
 
 type Coords struct {
-	X int32
-	Y int32
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+}
+
+func (c Coords) neighbours() [4]Coords {
+	return [4]Coords{
+		{X: c.X, Y: c.Y - 1},
+		{X: c.X + 1, Y: c.Y},
+		{X: c.X, Y: c.Y + 1},
+		{X: c.X - 1, Y: c.Y},
+	}
 }
 
 type Destination struct {
 	To        Coords
 	Transport pb.Transport
 	UserId    int32
+	InService time.Time // the route is out of service before, zero for a route never upgraded
+}
+
+// inService reports whether passengers can ride the route at the moment
+func (d *Destination) inService(now time.Time) bool {
+	return !now.Before(d.InService)
 }
 
 type TransportNetwork struct {
@@ -29,9 +42,33 @@ func NewTransportNetwork() *TransportNetwork {
 	}
 }
 
+// networkOf returns the network of the routes built on the map blocks
+func networkOf(blocks []*pb.Block) *TransportNetwork {
+	tn := NewTransportNetwork()
+	for _, block := range blocks {
+		from := coordsOf(block.Position)
+		for _, connector := range block.Connectors {
+			// every route is listed by both blocks
+			_ = tn.ConnectBlocks(connector.UserId, from, coordsOf(connector.Destination), connector.Transport)
+		}
+	}
+
+	// and the routes being upgraded tell when they are back in service
+	for _, block := range blocks {
+		for _, connector := range block.Connectors {
+			if connector.InServiceFrom != nil {
+				route, _ := tn.route(coordsOf(block.Position), coordsOf(connector.Destination))
+				route.InService = connector.InServiceFrom.AsTime()
+			}
+		}
+	}
+
+	return tn
+}
+
 func (tn *TransportNetwork) ConnectBlocks(userId int32, p1 Coords, p2 Coords, transport pb.Transport) error {
 	if tn.isPathExists(p1, p2) || tn.isPathExists(p2, p1) {
-		return fmt.Errorf("path between %v and %v already exists", p1, p2)
+		return newError(KindAlreadyExists, ReasonRouteExists, "", "route between (%d, %d) and (%d, %d) already exists", p1.X, p1.Y, p2.X, p2.Y)
 	}
 
 	path1to2 := &Destination{
@@ -51,117 +88,107 @@ func (tn *TransportNetwork) ConnectBlocks(userId int32, p1 Coords, p2 Coords, tr
 	return nil
 }
 
-func (tn *TransportNetwork) isPathExists(from Coords, to Coords) bool {
-	for _, point := range tn.blocks[from] {
+// DisconnectBlocks removes the route between the blocks in both directions
+func (tn *TransportNetwork) DisconnectBlocks(p1 Coords, p2 Coords) {
+	tn.removeDestination(p1, p2)
+	tn.removeDestination(p2, p1)
+}
+
+func (tn *TransportNetwork) removeDestination(from Coords, to Coords) {
+	destinations := tn.blocks[from]
+	for i, point := range destinations {
 		if point.To == to {
-			return true
+			destinations = append(destinations[:i], destinations[i+1:]...)
+			break
 		}
 	}
 
-	return false
+	if len(destinations) == 0 {
+		delete(tn.blocks, from)
+	} else {
+		tn.blocks[from] = destinations
+	}
 }
 
-type Path struct {
-	Start Coords
-	Hops  []*Destination
-}
+// UpgradeRoute changes the transport of the route between the blocks in both
+// directions, passengers can't ride it until inService. The routes are
+// replaced, so the paths of the passengers on the way keep the old transport.
+func (tn *TransportNetwork) UpgradeRoute(p1 Coords, p2 Coords, transport pb.Transport, inService time.Time) error {
+	if !tn.isPathExists(p1, p2) || !tn.isPathExists(p2, p1) {
+		return newError(KindNotFound, ReasonRouteNotFound, "", "there is no route between (%d, %d) and (%d, %d)", p1.X, p1.Y, p2.X, p2.Y)
+	}
 
-// Reward returns mapping of userid to reward they shall get
-func (p Path) Reward() map[int32]int {
-	rewards := map[int32]float64{}
+	tn.replaceDestination(p1, p2, transport, inService)
+	tn.replaceDestination(p2, p1, transport, inService)
 
-	prev := p.Start
+	return nil
+}
 
-	for _, point := range p.Hops {
-		xdiff := math.Abs(float64(prev.X) - float64(point.To.X))
-		ydiff := math.Abs(float64(prev.Y) - float64(point.To.Y))
-		distance := math.Sqrt(xdiff*xdiff + ydiff*ydiff)
+func (tn *TransportNetwork) replaceDestination(from Coords, to Coords, transport pb.Transport, inService time.Time) {
+	for i, point := range tn.blocks[from] {
+		if point.To == to {
+			tn.blocks[from][i] = &Destination{
+				To:        to,
+				Transport: transport,
+				UserId:    point.UserId,
+				InService: inService,
+			}
+			return
+		}
+	}
+}
 
-		oldReward, ok := rewards[point.UserId]
-		if !ok {
-			oldReward = 0
+// route returns the route from the block to the other one
+func (tn *TransportNetwork) route(from Coords, to Coords) (*Destination, bool) {
+	for _, point := range tn.blocks[from] {
+		if point.To == to {
+			return point, true
 		}
+	}
 
-		rewards[point.UserId] = oldReward + distance*float64(transportReward(point.Transport))
+	return nil, false
+}
 
-		prev = point.To
+func (tn *TransportNetwork) isPathExists(from Coords, to Coords) bool {
+	for _, point := range tn.blocks[from] {
+		if point.To == to {
+			return true
+		}
 	}
 
-	rewardsInt := map[int32]int{}
-	for k, v := range rewards {
-		rewardsInt[k] = int(v)
-	}
+	return false
+}
 
-	return rewardsInt
+type Path struct {
+	Start Coords
+	Hops  []*Destination
 }
 
 // Duration returns amount of time needed to achieve the destination
-func (p Path) Duration() time.Duration {
+func (p Path) Duration(rules *Rules) time.Duration {
 	var duration time.Duration
-	duration = 0
 
 	prev := p.Start
-
-	for _, point := range p.Hops {
-		xdiff := math.Abs(float64(prev.X) - float64(point.To.X))
-		ydiff := math.Abs(float64(prev.Y) - float64(point.To.Y))
-		distance := math.Sqrt(xdiff*xdiff + ydiff*ydiff)
-
-		duration += time.Duration(distance * float64(transportDuration(point.Transport)))
-
-		prev = point.To
+	for _, hop := range p.Hops {
+		duration += hopDuration(rules, prev, hop)
+		prev = hop.To
 	}
 
 	return duration
 }
 
-func (tn *TransportNetwork) RandomPath(from Coords, fuel int) Path {
-	cur := from
-	prev := cur // Whatever, doesn't matter for first hop
-	hops := []*Destination{}
-
-	for fuel > 0 {
-		fuel--
-		possibleHops, ok := tn.blocks[cur]
-
-		// oh what a shame no way to go but back
-		if !ok || len(possibleHops) <= 1 {
-			// luckily theres always an escape :3
-			break
-		}
-
-		// in other cases sample random direction to go to
-		nextIdx := rand.Intn(len(possibleHops) - 1) // -1 cuz we aint gonna go back
-
-		// fun indexing stuff where we skip the shit that's back
-		// good thing we're in 2d dis loop is smol
-		i := 0
-
-		// in case the idx is zero and the loop ahead ain't gonna run
-		if nextIdx == 0 && possibleHops[0].To == prev {
-			i = 1
-		}
-
-		for nextIdx > 0 {
-			i++
-			// we ain't counting bitch that's going back
-			if possibleHops[i].To != prev {
-				nextIdx--
-			}
-		}
-
-		// Could we overflow cause the edge to prev is last? HELL NAH
-		// Cause nextIdx is sampled from [0, len - 1), we ain't ever
-		// gonna get such index c:
+// hopDuration returns the time the passenger spends on the hop from the block
+func hopDuration(rules *Rules, from Coords, hop *Destination) time.Duration {
+	return time.Duration(hopDistance(from, hop.To) * float64(rules.transportDuration(hop.Transport)))
+}
 
-		hop := possibleHops[i]
-		prev = cur
-		cur = hop.To
-		hops = append(hops, hop)
-	}
+// hopReward returns the money the owner of the hop transport gets for the passenger
+func hopReward(rules *Rules, from Coords, hop *Destination) int32 {
+	return int32(math.Round(hopDistance(from, hop.To) * float64(rules.transportReward(hop.Transport))))
+}
 
-	return Path{
-		Start: from,
-		Hops:  hops,
-	}
+func hopDistance(from Coords, to Coords) float64 {
+	xdiff := float64(from.X) - float64(to.X)
+	ydiff := float64(from.Y) - float64(to.Y)
+	return math.Sqrt(xdiff*xdiff + ydiff*ydiff)
 }
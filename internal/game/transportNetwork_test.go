@@ -0,0 +1,46 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"testing"
+	"time"
+)
+
+func TestUpgradeRouteKeepsPathsOnTheWay(t *testing.T) {
+	rules := DefaultRules()
+	now := time.Now()
+	from, to := Coords{X: 0, Y: 0}, Coords{X: 3, Y: 0}
+
+	tn := NewTransportNetwork()
+	if err := tn.ConnectBlocks(1, from, to, pb.Transport_BUS); err != nil {
+		t.Fatalf("connect blocks error: %v", err)
+	}
+	path, ok := tn.BestPath(rules, now, from, to)
+	if !ok {
+		t.Fatalf("no path between the connected blocks")
+	}
+	ts := newTravellers()
+	traveller := ts.setOff(rules, NewRewardQueue(), path, now)
+	arrival := traveller.arrivals[0]
+
+	if err := tn.UpgradeRoute(from, to, pb.Transport_METRO, time.Time{}); err != nil {
+		t.Fatalf("upgrade route error: %v", err)
+	}
+
+	for _, ends := range [][2]Coords{{from, to}, {to, from}} {
+		route, ok := tn.route(ends[0], ends[1])
+		if !ok || route.Transport != pb.Transport_METRO || route.UserId != 1 {
+			t.Errorf("route from %v to %v = %+v, want the metro of user 1", ends[0], ends[1], route)
+		}
+	}
+
+	if hop := traveller.path.Hops[0]; hop.Transport != pb.Transport_BUS {
+		t.Errorf("the traveller on the way rides %s, want %s", hop.Transport, pb.Transport_BUS)
+	}
+	if passenger := traveller.position(now); passenger.Transport != pb.Transport_BUS {
+		t.Errorf("the traveller on the way is shown on %s, want %s", passenger.Transport, pb.Transport_BUS)
+	}
+	if !traveller.arrivals[0].Equal(arrival) {
+		t.Errorf("arrival changed from %v to %v", arrival, traveller.arrivals[0])
+	}
+}
@@ -0,0 +1,213 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// traveller is a passenger moving along the path. Hop i is passed from
+// arrivals[i-1] (start for the first hop) to arrivals[i].
+type traveller struct {
+	id       int64
+	path     Path
+	start    time.Time
+	arrivals []time.Time
+	rewards  []*Reward // queued payments, rewards[i] is for hop i or nil
+	track    *pb.Path  // sent to the clients once the traveller sets off
+}
+
+func newTraveller(rules *Rules, id int64, path Path, start time.Time) *traveller {
+	arrivals := make([]time.Time, 0, len(path.Hops))
+	points := []*pb.Coordintates{{X: path.Start.X, Y: path.Start.Y}}
+
+	at := start
+	prev := path.Start
+	for _, hop := range path.Hops {
+		at = at.Add(hopDuration(rules, prev, hop))
+		arrivals = append(arrivals, at)
+		points = append(points, &pb.Coordintates{X: hop.To.X, Y: hop.To.Y})
+		prev = hop.To
+	}
+
+	return &traveller{
+		id:       id,
+		path:     path,
+		start:    start,
+		arrivals: arrivals,
+		track: &pb.Path{
+			Points:      points,
+			StartTime:   timestamppb.New(start),
+			ArrivalTime: timestamppb.New(at),
+			PassengerId: id,
+		},
+	}
+}
+
+// scheduleRewards queues the payments for every hop, each due when the hop is
+// passed. The routes of map files are not paid for.
+func (t *traveller) scheduleRewards(rules *Rules, queue *RewardQueue) {
+	t.rewards = make([]*Reward, len(t.path.Hops))
+
+	prev := t.path.Start
+	for i, hop := range t.path.Hops {
+		if money := hopReward(rules, prev, hop); money > 0 && hop.UserId != nobody {
+			t.rewards[i] = &Reward{
+				userId:         hop.UserId,
+				money:          money,
+				activationTime: t.arrivals[i],
+			}
+			queue.Push(t.rewards[i])
+		}
+		prev = hop.To
+	}
+}
+
+// arrived reports whether the traveller has passed the whole path by now
+func (t *traveller) arrived(now time.Time) bool {
+	return len(t.arrivals) == 0 || !now.Before(t.arrivals[len(t.arrivals)-1])
+}
+
+// position returns where the traveller is at the moment, the traveller must not have arrived
+func (t *traveller) position(now time.Time) *pb.Passenger {
+	from, hopStart := t.path.Start, t.start
+	for i, hop := range t.path.Hops {
+		if now.Before(t.arrivals[i]) {
+			progress := 0.0
+			if hopLen := t.arrivals[i].Sub(hopStart); hopLen > 0 && now.After(hopStart) {
+				progress = float64(now.Sub(hopStart)) / float64(hopLen)
+			}
+
+			return &pb.Passenger{
+				Id:        t.id,
+				From:      &pb.Coordintates{X: from.X, Y: from.Y},
+				To:        &pb.Coordintates{X: hop.To.X, Y: hop.To.Y},
+				Progress:  float32(progress),
+				Transport: hop.Transport,
+				OwnerId:   hop.UserId,
+			}
+		}
+		from, hopStart = hop.To, t.arrivals[i]
+	}
+
+	return nil
+}
+
+// usesRoute returns the first hop not passed by now which goes between the
+// blocks in either direction
+func (t *traveller) usesRoute(now time.Time, p1, p2 Coords) (int, bool) {
+	prev := t.path.Start
+	for i, hop := range t.path.Hops {
+		passed := !now.Before(t.arrivals[i])
+		if !passed && (prev == p1 && hop.To == p2 || prev == p2 && hop.To == p1) {
+			return i, true
+		}
+		prev = hop.To
+	}
+
+	return 0, false
+}
+
+// cut ends the trip at the start of the hop, the payments for the hop and the
+// later ones are cancelled. The traveller on the hop at the moment gets off
+// right away. The track is replaced, the sent one is never changed.
+func (t *traveller) cut(now time.Time, hop int, queue *RewardQueue) {
+	for _, reward := range t.rewards[hop:] {
+		if reward != nil {
+			queue.Cancel(reward)
+		}
+	}
+
+	arrival := t.start
+	if hop > 0 {
+		arrival = t.arrivals[hop-1]
+	}
+	if arrival.Before(now) {
+		arrival = now
+	}
+
+	t.path.Hops = t.path.Hops[:hop]
+	t.arrivals = t.arrivals[:hop]
+	t.rewards = t.rewards[:hop]
+	t.track = &pb.Path{
+		Points:      t.track.Points[:hop+1],
+		StartTime:   t.track.StartTime,
+		ArrivalTime: timestamppb.New(arrival),
+		PassengerId: t.id,
+	}
+}
+
+// travellers are the passengers on the way
+type travellers struct {
+	lastId int64
+	list   []*traveller
+	cut    []*pb.Path // tracks replaced since the last state
+}
+
+func newTravellers() *travellers {
+	return &travellers{
+		list: []*traveller{},
+		cut:  []*pb.Path{},
+	}
+}
+
+// setOff starts the trip along the path and schedules the payments for it
+func (ts *travellers) setOff(rules *Rules, queue *RewardQueue, path Path, now time.Time) *traveller {
+	ts.lastId++
+	t := newTraveller(rules, ts.lastId, path, now)
+	t.scheduleRewards(rules, queue)
+	ts.list = append(ts.list, t)
+
+	return t
+}
+
+// move forgets the arrived travellers and returns the positions of the others
+func (ts *travellers) move(now time.Time) []*pb.Passenger {
+	passengers := make([]*pb.Passenger, 0, len(ts.list))
+
+	list := ts.list[:0]
+	for _, t := range ts.list {
+		if t.arrived(now) {
+			continue
+		}
+		list = append(list, t)
+		passengers = append(passengers, t.position(now))
+	}
+	clear(ts.list[len(list):])
+	ts.list = list
+
+	return passengers
+}
+
+// cutRoute ends the trips of the travellers who haven't passed the route
+// between the blocks yet at the start of it
+func (ts *travellers) cutRoute(now time.Time, p1, p2 Coords, queue *RewardQueue) {
+	for _, t := range ts.list {
+		if hop, ok := t.usesRoute(now, p1, p2); ok {
+			t.cut(now, hop, queue)
+			ts.cut = append(ts.cut, t.track)
+		}
+	}
+}
+
+// takeCut returns the tracks replaced since the previous call
+func (ts *travellers) takeCut() []*pb.Path {
+	cut := ts.cut
+	ts.cut = []*pb.Path{}
+	return cut
+}
+
+// snapshot returns the tracks and the positions of the travellers on the way
+func (ts *travellers) snapshot(now time.Time) ([]*pb.Path, []*pb.Passenger) {
+	tracks := make([]*pb.Path, 0, len(ts.list))
+	passengers := make([]*pb.Passenger, 0, len(ts.list))
+	for _, t := range ts.list {
+		tracks = append(tracks, t.track)
+		if !t.arrived(now) {
+			passengers = append(passengers, t.position(now))
+		}
+	}
+
+	return tracks, passengers
+}
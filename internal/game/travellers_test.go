@@ -0,0 +1,36 @@
+package game
+
+import (
+	pb "game_server/api/v1"
+	"testing"
+	"time"
+)
+
+func TestScheduleRewardsSkipsRoutesOfNobody(t *testing.T) {
+	rules := DefaultRules()
+	path := Path{
+		Start: Coords{X: 0, Y: 0},
+		Hops: []*Destination{
+			{To: Coords{X: 1, Y: 0}, Transport: pb.Transport_BUS, UserId: nobody},
+			{To: Coords{X: 2, Y: 0}, Transport: pb.Transport_BUS, UserId: 1},
+			{To: Coords{X: 3, Y: 0}, Transport: pb.Transport_METRO, UserId: nobody},
+		},
+	}
+
+	queue := NewRewardQueue()
+	traveller := newTravellers().setOff(rules, queue, path, time.Now())
+
+	for i, reward := range traveller.rewards {
+		if paid := reward != nil; paid != (path.Hops[i].UserId != nobody) {
+			t.Errorf("hop %d of user %d paid = %v", i, path.Hops[i].UserId, paid)
+		}
+	}
+
+	pending := queue.Pending()
+	if _, ok := pending[nobody]; ok {
+		t.Errorf("money is pending for nobody: %v", pending)
+	}
+	if want := int32(rules.RewardBus); pending[1] != want {
+		t.Errorf("pending money of user 1 is %d, want %d", pending[1], want)
+	}
+}
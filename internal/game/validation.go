@@ -0,0 +1,175 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	pb "game_server/api/v1"
+	"game_server/internal/database"
+)
+
+func validateCoords(gameMap Map, c *pb.Coordintates, field string) error {
+	if c == nil {
+		return newError(KindInvalidArgument, ReasonMissingField, field, "%s is required", field)
+	}
+
+	if !gameMap.Contains(c.X, c.Y) {
+		return newError(KindInvalidArgument, ReasonOutOfBounds, field, "%s (%d, %d) is out of the %dx%d map", field, c.X, c.Y, gameMap.Width, gameMap.Height)
+	}
+
+	return nil
+}
+
+func validateTransport(t pb.Transport) error {
+	if _, ok := pb.Transport_name[int32(t)]; !ok {
+		return newError(KindInvalidArgument, ReasonUnknownTransport, "transport", "unknown transport %d", t)
+	}
+
+	return nil
+}
+
+func validateNewTransport(gameMap Map, from, to *pb.Coordintates, transport pb.Transport) error {
+	if err := validateCoords(gameMap, from, "from"); err != nil {
+		return err
+	}
+	if err := validateCoords(gameMap, to, "to"); err != nil {
+		return err
+	}
+	if from.X == to.X && from.Y == to.Y {
+		return newError(KindInvalidArgument, ReasonSameBlock, "to", "can't connect block (%d, %d) with itself", from.X, from.Y)
+	}
+
+	return validateTransport(transport)
+}
+
+// validateOwnRoute checks the route between the blocks exists and is built by the user
+func validateOwnRoute(gameMap Map, network *TransportNetwork, userId int32, from, to *pb.Coordintates) (*Destination, error) {
+	if err := validateCoords(gameMap, from, "from"); err != nil {
+		return nil, err
+	}
+	if err := validateCoords(gameMap, to, "to"); err != nil {
+		return nil, err
+	}
+
+	route, ok := network.route(coordsOf(from), coordsOf(to))
+	if !ok {
+		return nil, newError(KindNotFound, ReasonRouteNotFound, "", "there is no route between (%d, %d) and (%d, %d)", from.X, from.Y, to.X, to.Y)
+	}
+	if route.UserId != userId {
+		return nil, newError(KindPermissionDenied, ReasonNotRouteOwner, "", "route between (%d, %d) and (%d, %d) is owned by user %d", from.X, from.Y, to.X, to.Y, route.UserId)
+	}
+
+	return route, nil
+}
+
+func validateExtendLicense(gameMap Map, session *pb.Session, userId int32, blocks []*pb.Coordintates) error {
+	if len(blocks) == 0 {
+		return newError(KindInvalidArgument, ReasonMissingField, "blocks", "blocks are required")
+	}
+
+	requested := map[Coords]bool{}
+	for i, block := range blocks {
+		field := fmt.Sprintf("blocks[%d]", i)
+		if err := validateCoords(gameMap, block, field); err != nil {
+			return err
+		}
+
+		coords := Coords{X: block.X, Y: block.Y}
+		if requested[coords] {
+			return newError(KindInvalidArgument, ReasonDuplicateBlock, field, "block (%d, %d) is requested twice", block.X, block.Y)
+		}
+		requested[coords] = true
+	}
+
+	var owned map[Coords]bool
+	for _, user := range session.Users {
+		license := userLicense(user)
+		if user.Id == userId {
+			owned = license
+		}
+
+		for coords := range requested {
+			if !license[coords] {
+				continue
+			}
+
+			if user.Id == userId {
+				return newError(KindFailedPrecondition, ReasonAlreadyOwned, "blocks", "block (%d, %d) is already in your license", coords.X, coords.Y)
+			}
+			return newError(KindFailedPrecondition, ReasonOwnedByOtherPlayer, "blocks", "block (%d, %d) is in the license of user %d", coords.X, coords.Y, user.Id)
+		}
+	}
+
+	return validateContiguous(owned, requested)
+}
+
+// validateContiguous checks that every requested block is connected with the
+// owned ones through the sides of requested blocks
+func validateContiguous(owned, requested map[Coords]bool) error {
+	queue := []Coords{}
+	reached := map[Coords]bool{}
+	for coords := range requested {
+		for _, neighbour := range coords.neighbours() {
+			if owned[neighbour] {
+				queue = append(queue, coords)
+				reached[coords] = true
+				break
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		coords := queue[0]
+		queue = queue[1:]
+
+		for _, neighbour := range coords.neighbours() {
+			if requested[neighbour] && !reached[neighbour] {
+				reached[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	for coords := range requested {
+		if !reached[coords] {
+			return newError(KindFailedPrecondition, ReasonNotContiguous, "blocks", "block (%d, %d) is not adjacent to your license", coords.X, coords.Y)
+		}
+	}
+
+	return nil
+}
+
+// validateBuildLicense checks the route endpoints against the user license and the build policy
+func validateBuildLicense(rules *Rules, user *pb.User, from, to *pb.Coordintates) error {
+	license := userLicense(user)
+	fromOwned := license[Coords{X: from.X, Y: from.Y}]
+	toOwned := license[Coords{X: to.X, Y: to.Y}]
+
+	switch {
+	case fromOwned && toOwned:
+		return nil
+	case rules.BuildPolicy == BuildOneEndpoint && (fromOwned || toOwned):
+		return nil
+	case rules.BuildPolicy == BuildOneEndpoint:
+		return newError(KindFailedPrecondition, ReasonNotLicensed, "", "neither (%d, %d) nor (%d, %d) is in your license", from.X, from.Y, to.X, to.Y)
+	case !fromOwned:
+		return newError(KindFailedPrecondition, ReasonNotLicensed, "from", "block (%d, %d) is not in your license", from.X, from.Y)
+	default:
+		return newError(KindFailedPrecondition, ReasonNotLicensed, "to", "block (%d, %d) is not in your license", to.X, to.Y)
+	}
+}
+
+func userLicense(user *pb.User) map[Coords]bool {
+	license := make(map[Coords]bool, len(user.License))
+	for _, block := range user.License {
+		license[Coords{X: block.X, Y: block.Y}] = true
+	}
+	return license
+}
+
+// sessionNotFound converts database miss into the game error
+func sessionNotFound(err error, format string, args ...any) error {
+	if errors.Is(err, database.ErrSessionNotFound) {
+		return newError(KindNotFound, ReasonSessionNotFound, "", format, args...)
+	}
+	return err
+}
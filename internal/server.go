@@ -4,96 +4,267 @@ import (
 	"context"
 	"errors"
 	pb "game_server/api/v1"
+	"game_server/internal/auth"
 	"game_server/internal/database"
 	"game_server/internal/game"
 	"log"
+	"time"
 
+	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
 	pb.UnimplementedApiServer
 
-	db              *database.DbConnector
+	db              database.Store
+	tokens          *auth.TokenManager
 	sessionsManager *game.SessionsManager
 }
 
-func NewServer(db *database.DbConnector) *Server {
+func NewServer(db database.Store, modes game.Modes, maps game.Maps, streamOptions game.StreamOptions, tokens *auth.TokenManager) *Server {
 	return &Server{
 		db:              db,
-		sessionsManager: game.NewSessionsManager(db),
+		tokens:          tokens,
+		sessionsManager: game.NewSessionsManager(db, modes, maps, streamOptions),
 	}
 }
 
-func (s *Server) GetSetup(context.Context, *emptypb.Empty) (*pb.Setup, error) {
+// PublicMethods are the rpcs which don't require an auth token
+var PublicMethods = []string{
+	pb.Api_Register_FullMethodName,
+	pb.Api_Login_FullMethodName,
+}
+
+func (s *Server) Register(_ context.Context, r *pb.Credentials) (*pb.AuthToken, error) {
+	log.Printf("register req, name: %s\n", r.Name)
+
+	if err := validateCredentials(r); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(r.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	userId, err := s.db.AddUser(r.Name, hash)
+	if err != nil {
+		if errors.Is(err, database.ErrUserExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "user %s already exists", r.Name)
+		}
+		return nil, InternalError(err)
+	}
+
+	return s.issueToken(userId)
+}
+
+func (s *Server) Login(_ context.Context, r *pb.Credentials) (*pb.AuthToken, error) {
+	log.Printf("login req, name: %s\n", r.Name)
+
+	user, err := s.db.GetUserByName(r.Name)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "wrong name or password")
+		}
+		return nil, InternalError(err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(r.Password)); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "wrong name or password")
+	}
+
+	return s.issueToken(user.Id)
+}
+
+func (s *Server) issueToken(userId int32) (*pb.AuthToken, error) {
+	token, expiresAt, err := s.tokens.Issue(userId)
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	return &pb.AuthToken{
+		Token:     token,
+		UserId:    userId,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+func validateCredentials(r *pb.Credentials) error {
+	if r.Name == "" || len(r.Name) > 64 {
+		return status.Error(codes.InvalidArgument, "name must be from 1 to 64 bytes long")
+	}
+	// bcrypt ignores everything after 72 bytes
+	if len(r.Password) < 6 || len(r.Password) > 72 {
+		return status.Error(codes.InvalidArgument, "password must be from 6 to 72 bytes long")
+	}
+	return nil
+}
+
+// userId returns the player authenticated by the auth interceptors
+func userId(ctx context.Context) (int32, error) {
+	userId, ok := auth.UserIdFromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	return userId, nil
+}
+
+func (s *Server) GetSetup(_ context.Context, r *pb.SetupReq) (*pb.Setup, error) {
+	mode := r.Mode
+	var session *pb.Session
+	if r.SessionId != 0 {
+		var err error
+		if session, err = s.db.GetSession(r.SessionId); err != nil {
+			return nil, StatusError(err)
+		}
+		mode = session.Mode
+	}
+
+	rules, err := s.sessionsManager.Rules(mode)
+	if err != nil {
+		return nil, StatusError(err)
+	}
+	if mode == "" {
+		mode = game.DefaultMode
+	}
+
+	// hand-authored maps have their own size
+	width, height := rules.Width, rules.Height
+	if session != nil {
+		gameMap := game.SessionMap(session)
+		width, height = gameMap.Width, gameMap.Height
+	}
+
 	return &pb.Setup{
-		TimeLimitMin: int32(game.TimeLimitMin),
-		LicenseCost:  game.LicenseCost,
-		OnpPenalty:   game.OnpPenalty,
-
-		CostBus:   game.Cost_BUS,
-		CostMetro: game.Cost_METRO,
-		CostTaxi:  game.Cost_TAXI,
-		CostTram:  game.Cost_TRAM,
-
-		DurationBus:   durationpb.New(game.Duration_BUS),
-		DurationMetro: durationpb.New(game.Duration_METRO),
-		DurationTaxi:  durationpb.New(game.Duration_TAXI),
-		DurationTram:  durationpb.New(game.Duration_TRAM),
+		Mode:               mode,
+		TimeLimitMin:       int32(rules.TimeLimitMin),
+		LicenseCost:        rules.LicenseCost,
+		OnpPenalty:         rules.OnpPenalty,
+		MaxPlayers:         int32(rules.MaxPlayers),
+		Width:              width,
+		Height:             height,
+		SideLen:            width, // for the clients which know only square maps
+		LicenseAreaSideLen: rules.LicenseAreaSideLen,
+		StartMoney:         rules.StartMoney,
+		UnlimitedMoney:     rules.UnlimitedMoney,
+		BuildPolicy:        rules.BuildPolicy,
+
+		CostBus:   rules.CostBus,
+		CostMetro: rules.CostMetro,
+		CostTaxi:  rules.CostTaxi,
+		CostTram:  rules.CostTram,
+
+		DurationBus:   durationpb.New(time.Duration(rules.DurationBus)),
+		DurationMetro: durationpb.New(time.Duration(rules.DurationMetro)),
+		DurationTaxi:  durationpb.New(time.Duration(rules.DurationTaxi)),
+		DurationTram:  durationpb.New(time.Duration(rules.DurationTram)),
+
+		RewardBus:   int32(rules.RewardBus),
+		RewardMetro: int32(rules.RewardMetro),
+		RewardTaxi:  int32(rules.RewardTaxi),
+		RewardTram:  int32(rules.RewardTram),
 	}, nil
 }
 
-func (s *Server) GetSession(_ context.Context, r *pb.UserId) (*pb.Session, error) {
-	log.Printf("get session req, user: %d\n", r.Id)
+func (s *Server) GetSession(ctx context.Context, r *pb.GetSessionReq) (*pb.Session, error) {
+	userId, err := userId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("get session req, user: %d, mode: %q, map: %q, seed: %d\n", userId, r.Mode, r.Map, r.Seed)
 
-	session, err := s.db.GetAliveSessionByUser(r.Id)
+	session, err := s.sessionsManager.GetSessionForUser(userId, game.SessionOptions{
+		Mode: r.Mode,
+		Map:  r.Map,
+		Seed: r.Seed,
+	})
 	if err != nil {
-		if errors.Is(err, database.ErrSessionNotFound) {
-			session, err = s.sessionsManager.FindSessionForUser(r.Id)
-			if err != nil {
-				return nil, InternalError(err)
-			}
-		} else {
-			return nil, InternalError(err)
-		}
+		return nil, StatusError(err)
 	}
-	log.Printf("found exists session %d for user %d\n", session.Id, r.Id)
 
 	return session, nil
 }
 
-func (s *Server) NewTransport(_ context.Context, r *pb.NewTransportReq) (*emptypb.Empty, error) {
-	log.Printf("new transport req, user: %d, transport: %s, from: %s, to: %s\n", r.UserId, r.Transport.String(), r.From.String(), r.To.String())
+func (s *Server) NewTransport(ctx context.Context, r *pb.NewTransportReq) (*emptypb.Empty, error) {
+	userId, err := userId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("new transport req, user: %d, transport: %s, from: %s, to: %s\n", userId, r.Transport.String(), r.From.String(), r.To.String())
 
-	err := s.sessionsManager.AddTransport(r.UserId, r.From, r.To, r.Transport)
+	err = s.sessionsManager.AddTransport(userId, r.From, r.To, r.Transport)
 	if err != nil {
-		return nil, InternalError(err)
+		return nil, StatusError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
-func (s *Server) ExtendLicense(_ context.Context, r *pb.ExtendLicenseReq) (*emptypb.Empty, error) {
-	log.Printf("extend license req, user: %d, license:\n", r.UserId)
+func (s *Server) RemoveTransport(ctx context.Context, r *pb.RemoveTransportReq) (*emptypb.Empty, error) {
+	userId, err := userId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("remove transport req, user: %d, from: %s, to: %s\n", userId, r.From.String(), r.To.String())
+
+	err = s.sessionsManager.RemoveTransport(userId, r.From, r.To)
+	if err != nil {
+		return nil, StatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) UpgradeTransport(ctx context.Context, r *pb.UpgradeTransportReq) (*emptypb.Empty, error) {
+	userId, err := userId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("upgrade transport req, user: %d, transport: %s, from: %s, to: %s\n", userId, r.Transport.String(), r.From.String(), r.To.String())
+
+	err = s.sessionsManager.UpgradeTransport(userId, r.From, r.To, r.Transport)
+	if err != nil {
+		return nil, StatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ExtendLicense(ctx context.Context, r *pb.ExtendLicenseReq) (*emptypb.Empty, error) {
+	userId, err := userId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("extend license req, user: %d, license:\n", userId)
 	for _, block := range r.Blocks {
 		log.Printf("%s\n", block.String())
 	}
 
-	err := s.sessionsManager.ExtendLicense(r.UserId, r.Blocks)
+	err = s.sessionsManager.ExtendLicense(userId, r.Blocks)
 	if err != nil {
-		return nil, InternalError(err)
+		return nil, StatusError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
 func (s *Server) StateStream(r *pb.StateStreamReq, srv pb.Api_StateStreamServer) error {
-	log.Printf("start session %d state stream for user: %d\n", r.SessionId.Id, r.UserId.Id)
+	userId, err := userId(srv.Context())
+	if err != nil {
+		return err
+	}
+	log.Printf("start session %d state stream for user: %d, last seq: %d\n", r.SessionId.GetId(), userId, r.LastSeq)
+
+	if err := s.sessionsManager.StreamState(r.SessionId.GetId(), userId, r.LastSeq, srv); err != nil {
+		return StatusError(err)
+	}
 
-	return s.sessionsManager.StreamState(r.SessionId.Id, r.UserId.Id, srv)
+	return nil
 }
 
 func InternalError(err error) error {
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"errors"
+	"game_server/internal/database"
+	"game_server/internal/game"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const errorDomain = "game_server"
+
+var errorKindCodes = map[game.ErrorKind]codes.Code{
+	game.KindInvalidArgument:    codes.InvalidArgument,
+	game.KindNotFound:           codes.NotFound,
+	game.KindAlreadyExists:      codes.AlreadyExists,
+	game.KindFailedPrecondition: codes.FailedPrecondition,
+	game.KindResourceExhausted:  codes.ResourceExhausted,
+	game.KindPermissionDenied:   codes.PermissionDenied,
+}
+
+// StatusError converts game errors to grpc status with ErrorInfo details
+// (and BadRequest for invalid arguments), any other error is internal
+func StatusError(err error) error {
+	var gameErr *game.Error
+	if !errors.As(err, &gameErr) {
+		if errors.Is(err, database.ErrSessionNotFound) {
+			return status.Errorf(codes.NotFound, "%v", err)
+		}
+		return InternalError(err)
+	}
+
+	code, ok := errorKindCodes[gameErr.Kind]
+	if !ok {
+		return InternalError(err)
+	}
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: gameErr.Reason,
+			Domain: errorDomain,
+		},
+	}
+	if gameErr.Kind == game.KindInvalidArgument && gameErr.Field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+				Field:       gameErr.Field,
+				Description: gameErr.Message,
+			}},
+		})
+	}
+
+	st, detailsErr := status.New(code, gameErr.Message).WithDetails(details...)
+	if detailsErr != nil {
+		return status.Error(code, gameErr.Message)
+	}
+
+	return st.Err()
+}
@@ -4,7 +4,9 @@ import (
 	pb "game_server/api/v1"
 	"game_server/config"
 	"game_server/internal"
+	"game_server/internal/auth"
 	"game_server/internal/database"
+	"game_server/internal/game"
 	"log"
 	"net"
 
@@ -17,9 +19,24 @@ func main() {
 		log.Fatalf("starting server error: %v", err)
 	}
 
-	db, err := database.NewDbConnector(config.DbHost, config.DbUser, config.DbPass)
+	rules, err := game.LoadRules(config.RulesFile)
 	if err != nil {
-		log.Fatalf("failed to connect database (%s): %v", config.DbHost, err)
+		log.Fatalf("failed to load game rules: %v", err)
+	}
+
+	modes, err := game.LoadModes(rules, config.ModesFile)
+	if err != nil {
+		log.Fatalf("failed to load game modes: %v", err)
+	}
+
+	maps, err := game.LoadMaps(config.MapsDir)
+	if err != nil {
+		log.Fatalf("failed to load maps: %v", err)
+	}
+
+	db, err := database.NewStore(config.DbType, config.DbHost, config.DbUser, config.DbPass)
+	if err != nil {
+		log.Fatalf("failed to connect database (%s %s): %v", config.DbType, config.DbHost, err)
 	}
 
 	lis, err := net.Listen("tcp", ":"+config.Port)
@@ -27,8 +44,28 @@ func main() {
 		log.Fatalf("failed to listen on port %s: %v", config.Port, err)
 	}
 
-	server := grpc.NewServer()
-	pb.RegisterApiServer(server, internal.NewServer(db))
+	if config.AuthSecret == "" {
+		log.Printf("AUTH_SECRET is not set, issued tokens won't survive server restart\n")
+	}
+	tokens, err := auth.NewTokenManager(config.AuthSecret, config.TokenTTL)
+	if err != nil {
+		log.Fatalf("failed to create token manager: %v", err)
+	}
+	interceptors := auth.NewInterceptors(tokens, internal.PublicMethods...)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.Unary()),
+		grpc.ChainStreamInterceptor(interceptors.Stream()),
+	)
+	streamOptions := game.StreamOptions{
+		BufferSize: config.StreamBuffer,
+		SlowPolicy: config.SlowStreamPolicy,
+		HistoryLen: config.StreamHistory,
+	}
+	if err := streamOptions.Validate(); err != nil {
+		log.Fatalf("invalid state stream options: %v", err)
+	}
+	pb.RegisterApiServer(server, internal.NewServer(db, modes, maps, streamOptions, tokens))
 	log.Printf("gRPC server listening at %s\n", config.Port)
 
 	if err := server.Serve(lis); err != nil {